@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	"helpdesk-ratings/internal/auth"
+	"helpdesk-ratings/internal/config"
+)
+
+// authInterceptors returns the interceptors that check the bearer token of
+// each call. Without JWT keys or API keys there are none when authentication
+// is explicitly disabled, otherwise the config is rejected.
+func authInterceptors(cfg config.AuthConfig) ([]grpc.UnaryServerInterceptor, []grpc.StreamServerInterceptor, error) {
+	if !cfg.Enabled() {
+		if !cfg.Disabled {
+			return nil, nil, errors.New("no AUTH_JWT_KEYS_FILE or AUTH_API_KEYS, set one of them or AUTH_DISABLED=true")
+		}
+		slog.Warn("Authentication is disabled, every RPC is served without a token")
+		return nil, nil, nil
+	}
+	authenticator, err := auth.New(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []grpc.UnaryServerInterceptor{authenticator.UnaryServerInterceptor()},
+		[]grpc.StreamServerInterceptor{authenticator.StreamServerInterceptor()}, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"helpdesk-ratings/internal/config"
+	"helpdesk-ratings/internal/database"
+)
+
+// withCache puts the query result cache in front of store, unless it is
+// disabled.
+func withCache(store database.Store, cfg config.CacheConfig) (database.Store, error) {
+	ttl, err := time.ParseDuration(cfg.TTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_TTL %q: %w", cfg.TTL, err)
+	}
+	size, err := strconv.Atoi(cfg.Size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_SIZE %q: %w", cfg.Size, err)
+	}
+	if ttl <= 0 || size <= 0 {
+		slog.Info("Query cache is disabled")
+		return store, nil
+	}
+
+	slog.Info("Query cache enabled", "ttl", ttl.String(), "size", size)
+	return database.NewCachedStore(store, ttl, size), nil
+}
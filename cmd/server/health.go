@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"helpdesk-ratings/internal/config"
+	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/health"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// newHealthChecker checks the database of store, stores without one are
+// always healthy.
+func newHealthChecker(store database.Store, cfg config.HealthConfig) (*health.Checker, error) {
+	interval, err := time.ParseDuration(cfg.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid HEALTH_CHECK_INTERVAL %q: %w", cfg.Interval, err)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid HEALTH_CHECK_INTERVAL %q: must be positive", cfg.Interval)
+	}
+
+	check := func(context.Context) error { return nil }
+	if checker, ok := store.(database.HealthChecker); ok {
+		check = checker.CheckHealth
+	}
+	return health.NewChecker(check, interval, pb.Service_ServiceDesc.ServiceName), nil
+}
@@ -1,42 +1,241 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"log/slog"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
+	"helpdesk-ratings/internal/certs"
 	"helpdesk-ratings/internal/config"
 	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/gateway"
+	"helpdesk-ratings/internal/logging"
+	"helpdesk-ratings/internal/metrics"
 	"helpdesk-ratings/internal/service"
+	"helpdesk-ratings/internal/tracing"
 	pb "helpdesk-ratings/proto/gen"
 )
 
+const (
+	// READ_HEADER_TIMEOUT bounds reading the request headers on the HTTP
+	// servers, so idle clients cannot hold connections open.
+	READ_HEADER_TIMEOUT = 10 * time.Second
+	// GATEWAY_READ_TIMEOUT bounds reading a whole gateway request, body
+	// included.
+	GATEWAY_READ_TIMEOUT = 30 * time.Second
+)
+
 func main() {
 	cfg := config.Load()
+	if err := logging.Setup(cfg.Logging); err != nil {
+		fatal("Invalid logging config", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg.Database, os.Args[2:]); err != nil {
+			fatal("Migration failed", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "rollup" {
+		if err := runRollup(cfg.Database, os.Args[2:]); err != nil {
+			fatal("Rollup failed", err)
+		}
+		return
+	}
+
+	// A second signal stops the process without waiting for the shutdown.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTimeout, err := parseShutdownTimeout(cfg.Server)
+	if err != nil {
+		fatal("Invalid server config", err)
+	}
+	shutdownDrainDelay, err := parseShutdownDrainDelay(cfg.Server)
+	if err != nil {
+		fatal("Invalid server config", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		fatal("Invalid tracing config", err)
+	}
+
+	slog.Info("Starting server", "port", cfg.Server.Port, "db", database.RedactDSN(cfg.Database.DataSource()))
+
+	if err := prepareSchema(cfg.Database); err != nil {
+		fatal("Database schema is not ready", err)
+	}
+
+	repo, err := database.Open(cfg.Database.DataSource())
+	if err != nil {
+		fatal("Failed to connect", err)
+	}
+
+	healthChecker, err := newHealthChecker(repo, cfg.Health)
+	if err != nil {
+		fatal("Invalid health config", err)
+	}
+	go healthChecker.Run(ctx)
+
+	serverMetrics := metrics.New()
+	if instrumented, ok := repo.(database.InstrumentedStore); ok {
+		serverMetrics.RegisterDBStats(instrumented.DBStats)
+		repo = instrumented.WithQueryObserver(serverMetrics)
+	}
 
-	log.Printf("Starting server with config: Port=%s, DB=%s", cfg.Server.Port, cfg.Database.FilePath)
+	repo, err = withCache(repo, cfg.Cache)
+	if err != nil {
+		fatal("Invalid cache config", err)
+	}
+	if cached, ok := repo.(*database.CachedStore); ok {
+		serverMetrics.RegisterCacheStats(cached.Stats)
+	}
 
-	repo, err := database.NewRepository(cfg.Database.FilePath)
+	weekStart, err := service.ParseWeekStart(cfg.Report.WeekStart)
 	if err != nil {
-		log.Fatalf("Failed to connect: %v", err)
+		fatal("Invalid report config", err)
 	}
-	defer repo.Close()
 
-	ratingsService := service.NewRatingsService(repo)
+	ratingsService := service.NewRatingsService(repo, service.WithWeekStart(weekStart))
 
 	lis, err := net.Listen("tcp", ":"+cfg.Server.Port)
 	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
+		fatal("Failed to listen", err)
+	}
+	healthLis, err := net.Listen("tcp", ":"+cfg.Health.Port)
+	if err != nil {
+		fatal("Failed to listen for health checks", err)
 	}
 
-	s := grpc.NewServer()
+	reloader, err := newTLSReloader(cfg.Server.TLS)
+	if err != nil {
+		fatal("Invalid TLS config", err)
+	}
+
+	authUnary, authStream, err := authInterceptors(cfg.Auth)
+	if err != nil {
+		fatal("Invalid auth config", err)
+	}
+
+	// The auth interceptors run last, so rejected calls are logged and
+	// counted.
+	unaryInterceptors := append([]grpc.UnaryServerInterceptor{logging.UnaryServerInterceptor(), serverMetrics.UnaryServerInterceptor()}, authUnary...)
+	streamInterceptors := append([]grpc.StreamServerInterceptor{logging.StreamServerInterceptor(), serverMetrics.StreamServerInterceptor()}, authStream...)
+	serverOptions := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	grpcOptions := serverOptions
+	if reloader != nil {
+		go reloader.Run(ctx, certs.RELOAD_INTERVAL)
+		grpcOptions = append(slices.Clip(serverOptions), grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+	}
+	s := grpc.NewServer(grpcOptions...)
 	pb.RegisterServiceServer(s, ratingsService)
+	healthChecker.Register(s)
 	reflection.Register(s)
 
-	log.Printf("Server starting on :%d", cfg.Server.Port)
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+	// Kubelet gRPC probes cannot use TLS, so the health service is also
+	// served in plaintext on its own port, without the other services.
+	healthServer := grpc.NewServer()
+	healthChecker.Register(healthServer)
+
+	// The certificate the gateway verified is read before the other
+	// interceptors run.
+	gatewayOptions := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(certs.ForwardedUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(certs.ForwardedStreamServerInterceptor()),
+	}, serverOptions...)
+	gatewayServer := grpc.NewServer(gatewayOptions...)
+	pb.RegisterServiceServer(gatewayServer, ratingsService)
+	conn, err := serveGateway(gatewayServer)
+	if err != nil {
+		fatal("Failed to create gateway client", err)
+	}
+
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("GET /metrics", serverMetrics.Handler())
+
+	servers := &servers{
+		health:     healthChecker,
+		healthGRPC: healthServer,
+		grpc:       s,
+		gateway: &http.Server{
+			Addr:              ":" + cfg.Server.HTTPPort,
+			Handler:           gateway.NewHandler(pb.NewServiceClient(conn)),
+			ReadHeaderTimeout: READ_HEADER_TIMEOUT,
+			ReadTimeout:       GATEWAY_READ_TIMEOUT,
+		},
+		gatewayConn: conn,
+		gatewayGRPC: gatewayServer,
+		metrics: &http.Server{
+			Addr:              ":" + cfg.Metrics.Port,
+			Handler:           metricsMux,
+			ReadHeaderTimeout: READ_HEADER_TIMEOUT,
+		},
+		store:           repo,
+		shutdownTracing: shutdownTracing,
+	}
+
+	if reloader != nil {
+		servers.gateway.TLSConfig = reloader.TLSConfig()
 	}
+	go func() {
+		slog.Info("HTTP gateway starting", "port", cfg.Server.HTTPPort, "tls", reloader != nil)
+		serve := servers.gateway.ListenAndServe
+		if reloader != nil {
+			// The certificates come from the TLS config.
+			serve = func() error { return servers.gateway.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to serve HTTP", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("Metrics starting", "port", cfg.Metrics.Port)
+		if err := servers.metrics.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to serve metrics", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("Health server starting", "port", cfg.Health.Port)
+		if err := healthServer.Serve(healthLis); err != nil {
+			fatal("Failed to serve health checks", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("Server starting", "port", cfg.Server.Port, "tls", reloader != nil, "mtls", cfg.Server.TLS.ClientCAFile != "")
+		if err := s.Serve(lis); err != nil {
+			fatal("Failed to serve", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	servers.shutdown(shutdownDrainDelay, shutdownTimeout)
+}
+
+// fatal logs err and exits, like log.Fatal.
+func fatal(message string, err error) {
+	slog.Error(message, "error", err)
+	os.Exit(1)
 }
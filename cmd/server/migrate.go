@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"helpdesk-ratings/internal/config"
+	"helpdesk-ratings/internal/database"
+)
+
+// Reverting the initial migration deletes all data, down refuses it without
+// DROP_DATA_FLAG.
+const (
+	MIGRATE_USAGE  = "usage: server migrate up|down [--drop-data]|status"
+	DROP_DATA_FLAG = "--drop-data"
+)
+
+// runMigrate implements the migrate subcommand against the configured
+// database.
+func runMigrate(cfg config.DatabaseConfig, args []string) error {
+	if len(args) == 0 || len(args) > 2 || (len(args) == 2 && (args[0] != "down" || args[1] != DROP_DATA_FLAG)) {
+		return errors.New(MIGRATE_USAGE)
+	}
+
+	migrator, err := database.NewMigrator(cfg.DataSource())
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			slog.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			slog.Info("Database is up to date", "version", migrator.Latest())
+		}
+		return nil
+	case "down":
+		migration, err := migrator.Down(len(args) == 2)
+		if errors.Is(err, database.ErrDropsData) {
+			return fmt.Errorf("%w, pass %s to revert it anyway", err, DROP_DATA_FLAG)
+		}
+		if err != nil {
+			return err
+		}
+		if migration == nil {
+			slog.Info("No migration to revert")
+			return nil
+		}
+		slog.Info("Reverted migration", "version", migration.Version, "name", migration.Name)
+		return nil
+	case "status":
+		statuses, err := migrator.Status()
+		if errors.Is(err, database.ErrNoVersionTable) {
+			fmt.Println("No schema_migrations table, `server migrate up` creates it")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.Applied() {
+				applied = "applied " + status.AppliedAt.Format(database.TIMESTAMP_FORMAT)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	default:
+		return errors.New(MIGRATE_USAGE)
+	}
+}
+
+// prepareSchema refuses to start on a database that is not at the latest
+// schema version, unless AutoMigrate is set and it can be migrated.
+func prepareSchema(cfg config.DatabaseConfig) error {
+	migrator, err := database.NewMigrator(cfg.DataSource())
+	if errors.Is(err, database.ErrNoMigrations) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	if !cfg.AutoMigrate {
+		if err := migrator.Check(); err != nil {
+			return fmt.Errorf("%w, run `server migrate up` or set DB_AUTO_MIGRATE=true", err)
+		}
+		return nil
+	}
+
+	applied, err := migrator.Up()
+	for _, migration := range applied {
+		slog.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	return err
+}
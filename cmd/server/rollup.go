@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"helpdesk-ratings/internal/config"
+	"helpdesk-ratings/internal/database"
+)
+
+const ROLLUP_USAGE = "usage: server rollup rebuild <start-day> <end-day>, days as YYYY-MM-DD"
+
+// runRollup implements the rollup subcommand. Rebuilding is only needed after
+// ratings were changed without going through the service, e.g. by a manual
+// import, the service keeps the rollups up to date itself.
+func runRollup(cfg config.DatabaseConfig, args []string) error {
+	if len(args) != 3 || args[0] != "rebuild" {
+		return errors.New(ROLLUP_USAGE)
+	}
+
+	store, err := database.Open(cfg.DataSource())
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	rollups, ok := store.(database.RollupStore)
+	if !ok {
+		return fmt.Errorf("%s keeps no rollups", database.RedactDSN(cfg.DataSource()))
+	}
+
+	rows, err := rollups.RebuildDailyScores(args[1], args[2])
+	if err != nil {
+		return err
+	}
+	slog.Info("Rebuilt daily rollups", "rollups", rows, "start_day", args[1], "end_day", args[2])
+	return nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"helpdesk-ratings/internal/config"
+	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/health"
+)
+
+// CLEANUP_TIMEOUT bounds each step after the RPCs are drained, so a stuck
+// exporter cannot hold the process.
+const CLEANUP_TIMEOUT = 5 * time.Second
+
+// servers is what main starts, and what shutdown stops in order.
+type servers struct {
+	health          *health.Checker
+	healthGRPC      *grpc.Server
+	grpc            *grpc.Server
+	gateway         *http.Server
+	gatewayConn     *grpc.ClientConn
+	gatewayGRPC     *grpc.Server
+	metrics         *http.Server
+	store           database.Store
+	shutdownTracing func(context.Context) error
+}
+
+func parseShutdownTimeout(cfg config.ServerConfig) (time.Duration, error) {
+	return parseShutdownDuration("SHUTDOWN_TIMEOUT", cfg.ShutdownTimeout)
+}
+
+func parseShutdownDrainDelay(cfg config.ServerConfig) (time.Duration, error) {
+	return parseShutdownDuration("SHUTDOWN_DRAIN_DELAY", cfg.ShutdownDrainDelay)
+}
+
+func parseShutdownDuration(name, value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative", name, value)
+	}
+	return duration, nil
+}
+
+// shutdown reports NOT_SERVING and keeps serving for drainDelay, so load
+// balancers and Kubernetes endpoints see the pod leave before it stops
+// accepting requests. The running requests then get timeout to finish, RPCs
+// still running after that are cancelled. Health and metrics are served until
+// the requests are drained, then the database is closed and the spans are
+// flushed.
+func (s *servers) shutdown(drainDelay, timeout time.Duration) {
+	slog.Info("Shutting down", "drain_delay", drainDelay.String(), "timeout", timeout.String())
+
+	s.health.Shutdown()
+	slog.Info("Health is NOT_SERVING")
+	if drainDelay > 0 {
+		time.Sleep(drainDelay)
+		slog.Info("Drain delay passed, no longer accepting requests")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Gateway requests are RPCs on the gRPC server, so they are drained
+	// first.
+	if err := s.gateway.Shutdown(ctx); err != nil {
+		slog.Warn("HTTP gateway did not drain in time, closing it", "error", err)
+		s.gateway.Close()
+	} else {
+		slog.Info("HTTP gateway stopped")
+	}
+	s.gatewayConn.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.gatewayGRPC.GracefulStop()
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		slog.Info("gRPC server drained")
+	case <-ctx.Done():
+		slog.Warn("gRPC server did not drain in time, cancelling the running RPCs")
+		s.gatewayGRPC.Stop()
+		s.grpc.Stop()
+		<-stopped
+		slog.Info("gRPC server stopped")
+	}
+
+	s.cleanup("Health server stopped", func(context.Context) error {
+		s.healthGRPC.Stop()
+		return nil
+	})
+	s.cleanup("Metrics server stopped", func(ctx context.Context) error { return s.metrics.Shutdown(ctx) })
+	s.cleanup("Database closed", func(context.Context) error { return s.store.Close() })
+	s.cleanup("Spans flushed", s.shutdownTracing)
+	slog.Info("Shutdown complete")
+}
+
+// cleanup runs step within CLEANUP_TIMEOUT and logs done, or its error.
+func (s *servers) cleanup(done string, step func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), CLEANUP_TIMEOUT)
+	defer cancel()
+
+	if err := step(ctx); err != nil {
+		slog.Error("Shutdown step failed", "step", done, "error", err)
+		return
+	}
+	slog.Info(done)
+}
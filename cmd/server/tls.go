@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"helpdesk-ratings/internal/certs"
+	"helpdesk-ratings/internal/config"
+)
+
+// GATEWAY_BUFFER_SIZE is the buffer of the in-memory gateway connection.
+const GATEWAY_BUFFER_SIZE = 1 << 20
+
+// newTLSReloader loads the certificates of cfg, it returns nil when TLS is
+// disabled.
+func newTLSReloader(cfg config.TLSConfig) (*certs.Reloader, error) {
+	if !cfg.Enabled() {
+		if cfg.ClientCAFile != "" {
+			return nil, errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+		}
+		return nil, nil
+	}
+	return certs.NewReloader(cfg)
+}
+
+// serveGateway serves s to the HTTP gateway over an in-memory connection. It
+// cannot be reached from the network, so it needs no TLS, and the gateway
+// needs no client certificate when mTLS is required. The gateway forwards
+// the certificate of its own client instead.
+func serveGateway(s *grpc.Server) (*grpc.ClientConn, error) {
+	lis := bufconn.Listen(GATEWAY_BUFFER_SIZE)
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			fatal("Failed to serve the gateway", err)
+		}
+	}()
+
+	return grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+}
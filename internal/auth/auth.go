@@ -0,0 +1,262 @@
+// Package auth authenticates the bearer token of an RPC, a JWT or a static
+// API key, and authorizes the RPC by the role of its caller.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"helpdesk-ratings/internal/config"
+)
+
+// Roles are ordered, every role may do what the roles below it may.
+const (
+	AGENT Role = iota + 1
+	TEAM_LEAD
+	ANALYST
+	ADMIN
+)
+
+const (
+	// AGENT_ID_CLAIM is the agent a token of the agent role belongs to.
+	AGENT_ID_CLAIM = "agent_id"
+	// JWT_LEEWAY absorbs clock skew with the issuer.
+	JWT_LEEWAY = 30 * time.Second
+)
+
+var (
+	ErrNoToken      = errors.New("no bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrNoRole       = errors.New("token has no known role")
+)
+
+// JWT_METHODS are the accepted signing algorithms, all asymmetric.
+var JWT_METHODS = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type Role int
+
+var roleNames = map[Role]string{
+	AGENT:     "agent",
+	TEAM_LEAD: "team_lead",
+	ANALYST:   "analyst",
+	ADMIN:     "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+func ParseRole(name string) (Role, bool) {
+	for role, roleName := range roleNames {
+		if roleName == name {
+			return role, true
+		}
+	}
+	return 0, false
+}
+
+// Identity is the authenticated caller of an RPC.
+type Identity struct {
+	// Subject is the sub claim of a JWT, or the name of an API key.
+	Subject string
+	Role    Role
+	// AgentID is set for tokens with the agent_id claim, and for agent API
+	// keys.
+	AgentID int64
+}
+
+type identityKey struct{}
+
+func withIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the caller of the RPC ctx belongs to.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Authenticator validates JWTs with the configured keys, and API keys.
+type Authenticator struct {
+	keys       jwt.VerificationKeySet
+	issuer     string
+	audience   string
+	rolesClaim string
+	apiKeys    map[[sha256.Size]byte]Identity
+}
+
+func New(cfg config.AuthConfig) (*Authenticator, error) {
+	a := &Authenticator{
+		issuer:     cfg.JWTIssuer,
+		audience:   cfg.JWTAudience,
+		rolesClaim: cfg.JWTRolesClaim,
+		apiKeys:    make(map[[sha256.Size]byte]Identity),
+	}
+
+	if cfg.JWTKeysFile != "" {
+		if cfg.JWTIssuer == "" {
+			return nil, errors.New("AUTH_JWT_ISSUER is required with AUTH_JWT_KEYS_FILE")
+		}
+		if cfg.JWTRolesClaim == "" {
+			return nil, errors.New("AUTH_JWT_ROLES_CLAIM cannot be empty")
+		}
+		keys, err := loadKeys(cfg.JWTKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		a.keys = jwt.VerificationKeySet{Keys: keys}
+	}
+
+	if cfg.APIKeys != "" {
+		for _, entry := range strings.Split(cfg.APIKeys, ",") {
+			identity, key, err := parseAPIKey(strings.TrimSpace(entry))
+			if err != nil {
+				return nil, err
+			}
+			hash := sha256.Sum256([]byte(key))
+			if _, ok := a.apiKeys[hash]; ok {
+				return nil, fmt.Errorf("API key %q is given twice in AUTH_API_KEYS", identity.Subject)
+			}
+			a.apiKeys[hash] = identity
+		}
+	}
+
+	if len(a.keys.Keys) == 0 && len(a.apiKeys) == 0 {
+		return nil, errors.New("no JWT keys or API keys are configured")
+	}
+	return a, nil
+}
+
+// parseAPIKey reads an AUTH_API_KEYS entry, name:role:key, or
+// name:agent:agent_id:key for an agent, which may only see its own scores.
+func parseAPIKey(entry string) (Identity, string, error) {
+	name, rest, _ := strings.Cut(entry, ":")
+	roleName, key, _ := strings.Cut(rest, ":")
+	role, ok := ParseRole(roleName)
+	if name == "" || !ok || key == "" {
+		return Identity{}, "", fmt.Errorf("invalid API key %q in AUTH_API_KEYS, expected name:role:key", name)
+	}
+
+	identity := Identity{Subject: name, Role: role}
+	if role == AGENT {
+		agentID, agentKey, _ := strings.Cut(key, ":")
+		id, err := strconv.ParseInt(agentID, 10, 64)
+		if err != nil || id <= 0 || agentKey == "" {
+			return Identity{}, "", fmt.Errorf("invalid API key %q in AUTH_API_KEYS, agent keys need an agent id, name:agent:agent_id:key", name)
+		}
+		identity.AgentID, key = id, agentKey
+	}
+	return identity, key, nil
+}
+
+// loadKeys reads the PUBLIC KEY and CERTIFICATE blocks of a PEM file.
+func loadKeys(path string) ([]jwt.VerificationKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []jwt.VerificationKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key in %s: %w", path, err)
+			}
+			keys = append(keys, key)
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid certificate in %s: %w", path, err)
+			}
+			keys = append(keys, cert.PublicKey)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public key found in %s", path)
+	}
+	return keys, nil
+}
+
+// Authenticate returns the caller a bearer token stands for. Tokens with
+// three dot separated parts are JWTs, others API keys.
+func (a *Authenticator) Authenticate(token string) (Identity, error) {
+	if strings.Count(token, ".") == 2 && len(a.keys.Keys) > 0 {
+		return a.authenticateJWT(token)
+	}
+	if identity, ok := a.apiKeys[sha256.Sum256([]byte(token))]; ok {
+		return identity, nil
+	}
+	return Identity{}, ErrInvalidToken
+}
+
+func (a *Authenticator) authenticateJWT(token string) (Identity, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(JWT_METHODS),
+		jwt.WithIssuer(a.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(JWT_LEEWAY),
+	}
+	if a.audience != "" {
+		options = append(options, jwt.WithAudience(a.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return a.keys, nil }, options...)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	subject, _ := claims.GetSubject()
+	identity := Identity{Subject: subject, Role: highestRole(claims[a.rolesClaim])}
+	if identity.Role == 0 {
+		return Identity{}, ErrNoRole
+	}
+	if agentID, ok := claims[AGENT_ID_CLAIM].(float64); ok {
+		identity.AgentID = int64(agentID)
+	}
+	return identity, nil
+}
+
+// highestRole reads a role claim, a single role or a list of roles, and
+// returns the highest known one.
+func highestRole(claim any) Role {
+	var names []string
+	switch value := claim.(type) {
+	case string:
+		names = []string{value}
+	case []any:
+		for _, name := range value {
+			if name, ok := name.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var highest Role
+	for _, name := range names {
+		if role, ok := ParseRole(name); ok && role > highest {
+			highest = role
+		}
+	}
+	return highest
+}
@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"helpdesk-ratings/internal/config"
+)
+
+const TEST_ISSUER = "https://issuer.example.com"
+
+// testKeys are the signing keys of a test, generated locally. Their public
+// keys are written to keysFile.
+type testKeys struct {
+	rsa      *rsa.PrivateKey
+	ecdsa    *ecdsa.PrivateKey
+	ed25519  ed25519.PrivateKey
+	keysFile string
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate Ed25519 key: %v", err)
+	}
+
+	var keysPEM []byte
+	for _, key := range []crypto.PublicKey{rsaKey.Public(), ecdsaKey.Public(), ed25519Key.Public()} {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			t.Fatalf("Failed to marshal public key: %v", err)
+		}
+		keysPEM = append(keysPEM, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})...)
+	}
+	keysFile := filepath.Join(t.TempDir(), "keys.pem")
+	if err := os.WriteFile(keysFile, keysPEM, 0o600); err != nil {
+		t.Fatalf("Failed to write keys: %v", err)
+	}
+
+	return &testKeys{rsa: rsaKey, ecdsa: ecdsaKey, ed25519: ed25519Key, keysFile: keysFile}
+}
+
+// sign returns an RS256 token with the claims, valid for an hour from
+// TEST_ISSUER unless the claims say otherwise.
+func (k *testKeys) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	return k.signWith(t, jwt.SigningMethodRS256, k.rsa, claims)
+}
+
+func (k *testKeys) signWith(t *testing.T, method jwt.SigningMethod, key crypto.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	all := jwt.MapClaims{"iss": TEST_ISSUER, "exp": time.Now().Add(time.Hour).Unix()}
+	for name, value := range claims {
+		if value == nil {
+			delete(all, name)
+		} else {
+			all[name] = value
+		}
+	}
+	token, err := jwt.NewWithClaims(method, all).SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+func (k *testKeys) config() config.AuthConfig {
+	return config.AuthConfig{
+		JWTIssuer:     TEST_ISSUER,
+		JWTKeysFile:   k.keysFile,
+		JWTRolesClaim: "roles",
+		APIKeys:       "exporter:analyst:secret-analyst-key, ops:admin:secret-admin-key",
+	}
+}
+
+func newTestAuthenticator(t *testing.T, cfg config.AuthConfig) *Authenticator {
+	t.Helper()
+
+	a, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	return a
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	keys := newTestKeys(t)
+	a := newTestAuthenticator(t, keys.config())
+
+	tests := []struct {
+		name     string
+		token    string
+		expected Identity
+	}{
+		{"RSA", keys.sign(t, jwt.MapClaims{"sub": "ana", "roles": []string{"analyst"}}),
+			Identity{Subject: "ana", Role: ANALYST}},
+		{"ECDSA", keys.signWith(t, jwt.SigningMethodES256, keys.ecdsa, jwt.MapClaims{"sub": "lead", "roles": "team_lead"}),
+			Identity{Subject: "lead", Role: TEAM_LEAD}},
+		{"Ed25519", keys.signWith(t, jwt.SigningMethodEdDSA, keys.ed25519, jwt.MapClaims{"sub": "root", "roles": []string{"admin"}}),
+			Identity{Subject: "root", Role: ADMIN}},
+		{"HighestRole", keys.sign(t, jwt.MapClaims{"sub": "ana", "roles": []string{"agent", "unknown", "analyst", "team_lead"}}),
+			Identity{Subject: "ana", Role: ANALYST}},
+		{"Agent", keys.sign(t, jwt.MapClaims{"sub": "alice", "roles": []string{"agent"}, "agent_id": 3}),
+			Identity{Subject: "alice", Role: AGENT, AgentID: 3}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := a.Authenticate(test.token)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if identity != test.expected {
+				t.Fatalf("Expected %+v, got %+v", test.expected, identity)
+			}
+		})
+	}
+}
+
+func TestAuthenticateInvalidJWT(t *testing.T) {
+	keys := newTestKeys(t)
+	other := newTestKeys(t)
+	a := newTestAuthenticator(t, keys.config())
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"UnknownKey", other.sign(t, jwt.MapClaims{"roles": "admin"}), ErrInvalidToken},
+		{"Issuer", keys.sign(t, jwt.MapClaims{"iss": "https://other.example.com", "roles": "admin"}), ErrInvalidToken},
+		{"Expired", keys.sign(t, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix(), "roles": "admin"}), ErrInvalidToken},
+		{"NoExpiry", keys.sign(t, jwt.MapClaims{"exp": nil, "roles": "admin"}), ErrInvalidToken},
+		{"Symmetric", keys.signWith(t, jwt.SigningMethodHS256, []byte("secret"), jwt.MapClaims{"roles": "admin"}), ErrInvalidToken},
+		{"NoRole", keys.sign(t, jwt.MapClaims{"roles": []string{"superuser"}}), ErrNoRole},
+		{"Malformed", "a.b.c", ErrInvalidToken},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := a.Authenticate(test.token); !errors.Is(err, test.err) {
+				t.Fatalf("Expected %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateAudience(t *testing.T) {
+	keys := newTestKeys(t)
+	cfg := keys.config()
+	cfg.JWTAudience = "helpdesk-ratings"
+	a := newTestAuthenticator(t, cfg)
+
+	if _, err := a.Authenticate(keys.sign(t, jwt.MapClaims{"aud": "helpdesk-ratings", "roles": "agent"})); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := a.Authenticate(keys.sign(t, jwt.MapClaims{"aud": "billing", "roles": "agent"})); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Expected ErrInvalidToken for another audience, got %v", err)
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	a := newTestAuthenticator(t, config.AuthConfig{APIKeys: "exporter:analyst:secret-analyst-key,ops:admin:secret-admin-key,carol:agent:3:secret:agent-key"})
+
+	identity, err := a.Authenticate("secret-admin-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := (Identity{Subject: "ops", Role: ADMIN}); identity != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, identity)
+	}
+	identity, err = a.Authenticate("secret:agent-key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := (Identity{Subject: "carol", Role: AGENT, AgentID: 3}); identity != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, identity)
+	}
+	for _, token := range []string{"", "secret", "ops", "a.b.c"} {
+		if _, err := a.Authenticate(token); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("Expected ErrInvalidToken for %q, got %v", token, err)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	keys := newTestKeys(t)
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []config.AuthConfig{
+		{},
+		{JWTKeysFile: keys.keysFile, JWTRolesClaim: "roles"},
+		{JWTIssuer: TEST_ISSUER, JWTKeysFile: empty, JWTRolesClaim: "roles"},
+		{JWTIssuer: TEST_ISSUER, JWTKeysFile: keys.keysFile},
+		{APIKeys: "ops:root:key"},
+		{APIKeys: "ops:admin:"},
+		{APIKeys: "ops:admin:key,other:analyst:key"},
+		{APIKeys: "carol:agent:secret-agent-key"},
+		{APIKeys: "carol:agent:0:secret-agent-key"},
+		{APIKeys: "carol:agent:3:"},
+	}
+	for _, test := range tests {
+		if _, err := New(test); err == nil {
+			t.Fatalf("Expected an error for %+v", test)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// PERMISSIONS is the lowest role allowed to call each RPC. RPCs that are
+// neither listed nor PUBLIC are denied to everyone.
+var PERMISSIONS = map[string]Role{
+	pb.Service_GetAggregatedScores_FullMethodName:    ANALYST,
+	pb.Service_StreamAggregatedScores_FullMethodName: ANALYST,
+	pb.Service_ExportAggregatedScores_FullMethodName: ANALYST,
+	pb.Service_GetOverallScore_FullMethodName:        ANALYST,
+	pb.Service_GetTicketScores_FullMethodName:        ANALYST,
+	pb.Service_CompareScores_FullMethodName:          ANALYST,
+	pb.Service_GetReviewerCalibration_FullMethodName: ANALYST,
+	pb.Service_GetAgentScores_FullMethodName:         TEAM_LEAD,
+	// Agents may only read their own scores, see authorizeRequest.
+	pb.Service_GetAgentAggregatedScores_FullMethodName: AGENT,
+	pb.Service_ListCategories_FullMethodName:           AGENT,
+
+	pb.Service_SubmitRating_FullMethodName:     ADMIN,
+	pb.Service_SubmitRatings_FullMethodName:    ADMIN,
+	pb.Service_CreateCategory_FullMethodName:   ADMIN,
+	pb.Service_RenameCategory_FullMethodName:   ADMIN,
+	pb.Service_ReweightCategory_FullMethodName: ADMIN,
+	pb.Service_RetireCategory_FullMethodName:   ADMIN,
+
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName:      AGENT,
+	reflectionalphapb.ServerReflection_ServerReflectionInfo_FullMethodName: AGENT,
+}
+
+// PUBLIC RPCs need no token, probes cannot send one.
+var PUBLIC = map[string]bool{
+	healthpb.Health_Check_FullMethodName: true,
+	healthpb.Health_List_FullMethodName:  true,
+	healthpb.Health_Watch_FullMethodName: true,
+}
+
+// UnaryServerInterceptor authenticates the bearer token of the call and
+// checks that its role may call the method. The caller is available to the
+// handler with FromContext.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorizeRequest(ctx, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (a *Authenticator) authorize(ctx context.Context, method string) (context.Context, error) {
+	if PUBLIC[method] {
+		return ctx, nil
+	}
+	required, ok := PERMISSIONS[method]
+	if !ok {
+		slog.WarnContext(ctx, "Denied a method without permissions")
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not allowed", method)
+	}
+
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "a bearer token is required")
+	}
+	identity, err := a.Authenticate(token)
+	if errors.Is(err, ErrNoRole) {
+		slog.WarnContext(ctx, "Denied a token without a role")
+		return nil, status.Errorf(codes.PermissionDenied, "the token has no role")
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Invalid token", "error", err)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
+	}
+
+	if identity.Role < required {
+		slog.WarnContext(ctx, "Permission denied", "subject", identity.Subject, "role", identity.Role.String(), "required_role", required.String())
+		return nil, status.Errorf(codes.PermissionDenied, "the %s role cannot call %s", identity.Role, method)
+	}
+	return withIdentity(ctx, identity), nil
+}
+
+// authorizeRequest checks the permissions that depend on the request.
+func authorizeRequest(ctx context.Context, req any) error {
+	identity, ok := FromContext(ctx)
+	if !ok || identity.Role > AGENT {
+		return nil
+	}
+	if req, ok := req.(*pb.AgentAggregatedScoresRequest); ok && (identity.AgentID == 0 || req.AgentId != identity.AgentID) {
+		slog.WarnContext(ctx, "Permission denied", "subject", identity.Subject, "role", identity.Role.String(), "agent_id", req.AgentId)
+		return status.Errorf(codes.PermissionDenied, "agents can only read their own scores")
+	}
+	return nil
+}
+
+func bearerToken(ctx context.Context) (string, error) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return "", ErrNoToken
+	}
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", ErrNoToken
+	}
+	return strings.TrimSpace(token), nil
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/service"
+	"helpdesk-ratings/internal/testutil"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// newTestConn serves the fixture store and a health service with the auth
+// interceptors over an in-memory gRPC connection.
+func newTestConn(t *testing.T, a *Authenticator) *grpc.ClientConn {
+	t.Helper()
+
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	return testutil.Serve(t, []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(a.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(a.StreamServerInterceptor()),
+	}, func(server *grpc.Server) {
+		pb.RegisterServiceServer(server, service.NewRatingsService(store))
+		healthpb.RegisterHealthServer(server, health.NewServer())
+	})
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func expectCode(t *testing.T, err error, expected codes.Code) {
+	t.Helper()
+	if code := status.Code(err); code != expected {
+		t.Fatalf("Expected %v, got %v", expected, err)
+	}
+}
+
+var (
+	startDate = timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	endDate   = timestamppb.New(time.Date(2025, 1, 3, 23, 59, 59, 0, time.UTC))
+
+	aggregatedScoresRequest = &pb.AggregatedScoresRequest{StartDate: startDate, EndDate: endDate}
+)
+
+func TestPermissions(t *testing.T) {
+	keys := newTestKeys(t)
+	client := pb.NewServiceClient(newTestConn(t, newTestAuthenticator(t, keys.config())))
+
+	admin := withToken(keys.sign(t, jwt.MapClaims{"sub": "root", "roles": []string{"admin"}}))
+	analyst := withToken(keys.sign(t, jwt.MapClaims{"sub": "ana", "roles": []string{"analyst"}}))
+	teamLead := withToken(keys.sign(t, jwt.MapClaims{"sub": "lead", "roles": []string{"team_lead"}}))
+	agent := withToken(keys.sign(t, jwt.MapClaims{"sub": "carol", "roles": []string{"agent"}, "agent_id": 3}))
+
+	_, err := client.GetAggregatedScores(context.Background(), aggregatedScoresRequest)
+	expectCode(t, err, codes.Unauthenticated)
+	_, err = client.GetAggregatedScores(withToken("not-a-key"), aggregatedScoresRequest)
+	expectCode(t, err, codes.Unauthenticated)
+
+	_, err = client.GetAggregatedScores(analyst, aggregatedScoresRequest)
+	expectCode(t, err, codes.OK)
+	_, err = client.GetAggregatedScores(admin, aggregatedScoresRequest)
+	expectCode(t, err, codes.OK)
+	_, err = client.GetAggregatedScores(teamLead, aggregatedScoresRequest)
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = client.GetAggregatedScores(agent, aggregatedScoresRequest)
+	expectCode(t, err, codes.PermissionDenied)
+
+	agentScoresRequest := &pb.AgentScoresRequest{StartDate: startDate, EndDate: endDate}
+	_, err = client.GetAgentScores(teamLead, agentScoresRequest)
+	expectCode(t, err, codes.OK)
+	_, err = client.GetAgentScores(agent, agentScoresRequest)
+	expectCode(t, err, codes.PermissionDenied)
+
+	_, err = client.CreateCategory(analyst, &pb.CreateCategoryRequest{Name: "Empathy", Weight: 1})
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = client.CreateCategory(admin, &pb.CreateCategoryRequest{Name: "Empathy", Weight: 1})
+	expectCode(t, err, codes.OK)
+
+	_, err = client.ListCategories(agent, &pb.ListCategoriesRequest{})
+	expectCode(t, err, codes.OK)
+}
+
+func TestAgentPermissions(t *testing.T) {
+	keys := newTestKeys(t)
+	client := pb.NewServiceClient(newTestConn(t, newTestAuthenticator(t, keys.config())))
+
+	carol := withToken(keys.sign(t, jwt.MapClaims{"sub": "carol", "roles": []string{"agent"}, "agent_id": 3}))
+	noAgentID := withToken(keys.sign(t, jwt.MapClaims{"sub": "carol", "roles": []string{"agent"}}))
+	lead := withToken(keys.sign(t, jwt.MapClaims{"sub": "lead", "roles": []string{"team_lead"}}))
+
+	request := func(agentID int64) *pb.AgentAggregatedScoresRequest {
+		return &pb.AgentAggregatedScoresRequest{AgentId: agentID, StartDate: startDate, EndDate: endDate}
+	}
+	_, err := client.GetAgentAggregatedScores(carol, request(3))
+	expectCode(t, err, codes.OK)
+	_, err = client.GetAgentAggregatedScores(carol, request(4))
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = client.GetAgentAggregatedScores(noAgentID, request(0))
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = client.GetAgentAggregatedScores(lead, request(4))
+	expectCode(t, err, codes.OK)
+
+	// An agent API key carries its agent id like the claim.
+	cfg := keys.config()
+	cfg.APIKeys += ",carol:agent:3:secret-agent-key"
+	client = pb.NewServiceClient(newTestConn(t, newTestAuthenticator(t, cfg)))
+	_, err = client.GetAgentAggregatedScores(withToken("secret-agent-key"), request(3))
+	expectCode(t, err, codes.OK)
+	_, err = client.GetAgentAggregatedScores(withToken("secret-agent-key"), request(4))
+	expectCode(t, err, codes.PermissionDenied)
+}
+
+func TestTokenWithoutRole(t *testing.T) {
+	keys := newTestKeys(t)
+	client := pb.NewServiceClient(newTestConn(t, newTestAuthenticator(t, keys.config())))
+
+	ctx := withToken(keys.sign(t, jwt.MapClaims{"sub": "nobody"}))
+	_, err := client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+	expectCode(t, err, codes.PermissionDenied)
+}
+
+func TestAPIKeyPermissions(t *testing.T) {
+	keys := newTestKeys(t)
+	client := pb.NewServiceClient(newTestConn(t, newTestAuthenticator(t, keys.config())))
+
+	_, err := client.GetAggregatedScores(withToken("secret-analyst-key"), aggregatedScoresRequest)
+	expectCode(t, err, codes.OK)
+	_, err = client.RetireCategory(withToken("secret-analyst-key"), &pb.RetireCategoryRequest{Id: 1})
+	expectCode(t, err, codes.PermissionDenied)
+
+	// The scheme is case-insensitive.
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer secret-admin-key")
+	_, err = client.RetireCategory(ctx, &pb.RetireCategoryRequest{Id: 1})
+	expectCode(t, err, codes.OK)
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic secret-admin-key")
+	_, err = client.RetireCategory(ctx, &pb.RetireCategoryRequest{Id: 2})
+	expectCode(t, err, codes.Unauthenticated)
+}
+
+func TestStreamPermissions(t *testing.T) {
+	keys := newTestKeys(t)
+	client := pb.NewServiceClient(newTestConn(t, newTestAuthenticator(t, keys.config())))
+
+	receive := func(ctx context.Context) error {
+		stream, err := client.StreamAggregatedScores(ctx, aggregatedScoresRequest)
+		if err != nil {
+			return err
+		}
+		for {
+			if _, err := stream.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	expectCode(t, receive(context.Background()), codes.Unauthenticated)
+	expectCode(t, receive(withToken("secret-analyst-key")), codes.OK)
+
+	stream, err := client.SubmitRatings(withToken("secret-analyst-key"))
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	_, err = stream.CloseAndRecv()
+	expectCode(t, err, codes.PermissionDenied)
+}
+
+func TestHealthIsPublic(t *testing.T) {
+	keys := newTestKeys(t)
+	client := healthpb.NewHealthClient(newTestConn(t, newTestAuthenticator(t, keys.config())))
+
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestEveryMethodHasPermissions(t *testing.T) {
+	for _, method := range pb.Service_ServiceDesc.Methods {
+		if _, ok := PERMISSIONS["/"+pb.Service_ServiceDesc.ServiceName+"/"+method.MethodName]; !ok {
+			t.Fatalf("Expected permissions for %s", method.MethodName)
+		}
+	}
+	for _, stream := range pb.Service_ServiceDesc.Streams {
+		if _, ok := PERMISSIONS["/"+pb.Service_ServiceDesc.ServiceName+"/"+stream.StreamName]; !ok {
+			t.Fatalf("Expected permissions for %s", stream.StreamName)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package certs serves TLS certificates from PEM files that are reloaded when
+// they are rotated, and exposes the certificate of mTLS clients.
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"helpdesk-ratings/internal/config"
+)
+
+// RELOAD_INTERVAL is how often the files are read for changes. Mounted
+// secrets are replaced by swapping a symlink, which file watches miss.
+const RELOAD_INTERVAL = 10 * time.Second
+
+// Reloader holds the certificate and the client CAs of a TLSConfig.
+type Reloader struct {
+	cfg config.TLSConfig
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	// loaded is the content of the files in use, to tell a rotation apart.
+	loaded [][]byte
+}
+
+// NewReloader loads the files of cfg, which must be usable.
+func NewReloader(cfg config.TLSConfig) (*Reloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("both TLS_CERT_FILE and TLS_KEY_FILE are required for TLS")
+	}
+	r := &Reloader{cfg: cfg}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig is the server configuration, every handshake uses the latest
+// files. Clients must present a certificate when a client CA is configured.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			c := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.certificate},
+			}
+			if r.clientCAs != nil {
+				c.ClientCAs = r.clientCAs
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return c, nil
+		},
+	}
+}
+
+// Run reloads the files every interval until ctx is done. A rotation that
+// cannot be loaded, e.g. a key written after its certificate, is logged and
+// tried again, the previous files stay in use.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := r.Reload()
+		if err != nil {
+			slog.Error("Failed to reload TLS certificates", "error", err)
+		} else if reloaded {
+			slog.Info("Reloaded TLS certificates", "not_after", r.notAfter())
+		}
+	}
+}
+
+// Reload reads the files and uses them if they changed since the last load.
+func (r *Reloader) Reload() (bool, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return false, err
+		}
+		contents[i] = content
+	}
+
+	r.mu.RLock()
+	unchanged := equal(contents, r.loaded)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	certificate, err := tls.X509KeyPair(contents[0], contents[1])
+	if err != nil {
+		return false, fmt.Errorf("invalid certificate %s or key %s: %w", r.cfg.CertFile, r.cfg.KeyFile, err)
+	}
+	var clientCAs *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(contents[2]) {
+			return false, fmt.Errorf("no certificate found in client CA file %s", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.certificate = &certificate
+	r.clientCAs = clientCAs
+	r.loaded = contents
+	return true, nil
+}
+
+func (r *Reloader) notAfter() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.certificate.Leaf.NotAfter
+}
+
+func equal(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClientCertificate returns the verified certificate the client of an RPC
+// presented over mTLS, to the gRPC server or to the HTTP gateway.
+func ClientCertificate(ctx context.Context) (*x509.Certificate, bool) {
+	if cert, ok := ctx.Value(forwardedKey{}).(*x509.Certificate); ok {
+		return cert, true
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	return info.State.VerifiedChains[0][0], true
+}
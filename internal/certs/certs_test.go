@@ -0,0 +1,266 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+
+	"helpdesk-ratings/internal/config"
+	"helpdesk-ratings/internal/testutil"
+)
+
+// testCA signs the certificates of a test, all generated locally.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM certificate and key of name, for a server or a
+// client.
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, content []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// newTestServer serves the health service with the TLS config of r over an
+// in-memory connection. The certificates of the clients are sent to clients.
+func newTestServer(t *testing.T, r *Reloader, clients chan<- *x509.Certificate) *bufconn.Listener {
+	t.Helper()
+
+	return testutil.Listen(t, []grpc.ServerOption{
+		grpc.Creds(credentials.NewTLS(r.TLSConfig())),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			cert, _ := ClientCertificate(ctx)
+			clients <- cert
+			return handler(ctx, req)
+		}),
+	}, func(server *grpc.Server) {
+		healthpb.RegisterHealthServer(server, health.NewServer())
+	})
+}
+
+// check calls the server with a new connection and returns the certificate
+// the server presented.
+func check(t *testing.T, lis *bufconn.Listener, client *tls.Config) (*x509.Certificate, error) {
+	t.Helper()
+
+	conn, err := grpc.NewClient("passthrough:///server",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(credentials.NewTLS(client)))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer conn.Close()
+
+	var p peer.Peer
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Peer(&p)); err != nil {
+		return nil, err
+	}
+	return p.AuthInfo.(credentials.TLSInfo).State.PeerCertificates[0], nil
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.TLSConfig{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, cert)
+	writeFile(t, cfg.KeyFile, key)
+	writeFile(t, cfg.ClientCAFile, ca.pem)
+
+	r, err := NewReloader(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clients := make(chan *x509.Certificate, 10)
+	lis := newTestServer(t, r, clients)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientCert, clientKey := ca.issue(t, "analytics-job", x509.ExtKeyUsageClientAuth)
+	clientPair, err := tls.X509KeyPair(clientCert, clientKey)
+	if err != nil {
+		t.Fatalf("Failed to load client certificate: %v", err)
+	}
+	client := &tls.Config{ServerName: "server", RootCAs: roots, Certificates: []tls.Certificate{clientPair}}
+
+	served, err := check(t, lis, client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if served.Subject.CommonName != "server" {
+		t.Fatalf("Expected the server certificate, got %v", served.Subject)
+	}
+	if identity := <-clients; identity == nil || identity.Subject.CommonName != "analytics-job" {
+		t.Fatalf("Expected the client certificate in the handler, got %v", identity)
+	}
+
+	if _, err := check(t, lis, &tls.Config{ServerName: "server", RootCAs: roots}); err == nil {
+		t.Fatalf("Expected a client without a certificate to be rejected")
+	}
+
+	if reloaded, err := r.Reload(); err != nil || reloaded {
+		t.Fatalf("Expected nothing to reload, got %v, %v", reloaded, err)
+	}
+
+	// A certificate written before its key is not used.
+	rotatedCert, rotatedKey := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, rotatedCert)
+	if _, err := r.Reload(); err == nil {
+		t.Fatalf("Expected an error for a certificate that does not match its key")
+	}
+	if served, err := check(t, lis, client); err != nil || served.SerialNumber.Cmp(mustParse(t, cert).SerialNumber) != 0 {
+		t.Fatalf("Expected the previous certificate to stay in use, got %v", err)
+	}
+	<-clients
+
+	writeFile(t, cfg.KeyFile, rotatedKey)
+	if reloaded, err := r.Reload(); err != nil || !reloaded {
+		t.Fatalf("Expected the rotated files to be loaded, got %v, %v", reloaded, err)
+	}
+	served, err = check(t, lis, client)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if served.SerialNumber.Cmp(mustParse(t, rotatedCert).SerialNumber) != 0 {
+		t.Fatalf("Expected the rotated certificate to be served")
+	}
+	<-clients
+}
+
+func TestReloaderWithoutClientCA(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.TLSConfig{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, cert)
+	writeFile(t, cfg.KeyFile, key)
+
+	r, err := NewReloader(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	clients := make(chan *x509.Certificate, 1)
+	lis := newTestServer(t, r, clients)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	if _, err := check(t, lis, &tls.Config{ServerName: "server", RootCAs: roots}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if identity := <-clients; identity != nil {
+		t.Fatalf("Expected no client certificate, got %v", identity.Subject)
+	}
+}
+
+func TestNewReloaderErrors(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	cert, key := ca.issue(t, "server", x509.ExtKeyUsageServerAuth)
+	writeFile(t, filepath.Join(dir, "tls.crt"), cert)
+	writeFile(t, filepath.Join(dir, "tls.key"), key)
+	writeFile(t, filepath.Join(dir, "empty.crt"), nil)
+
+	tests := []config.TLSConfig{
+		{CertFile: filepath.Join(dir, "tls.crt")},
+		{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: filepath.Join(dir, "tls.key")},
+		{CertFile: filepath.Join(dir, "tls.key"), KeyFile: filepath.Join(dir, "tls.crt")},
+		{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key"), ClientCAFile: filepath.Join(dir, "empty.crt")},
+	}
+	for _, test := range tests {
+		if _, err := NewReloader(test); err == nil {
+			t.Fatalf("Expected an error for %+v", test)
+		}
+	}
+}
+
+func mustParse(t *testing.T, certPEM []byte) *x509.Certificate {
+	t.Helper()
+
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return cert
+}
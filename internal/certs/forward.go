@@ -0,0 +1,66 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// FORWARDED_CERTIFICATE_METADATA carries the DER client certificate the HTTP
+// gateway verified to the in-memory gRPC server behind it. Binary metadata
+// keys end in -bin.
+const FORWARDED_CERTIFICATE_METADATA = "x-forwarded-client-certificate-bin"
+
+type forwardedKey struct{}
+
+// ForwardClientCertificate sets the verified client certificate of an HTTP
+// request in md, and drops any the client sent itself.
+func ForwardClientCertificate(md metadata.MD, state *tls.ConnectionState) {
+	md.Delete(FORWARDED_CERTIFICATE_METADATA)
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return
+	}
+	md.Set(FORWARDED_CERTIFICATE_METADATA, string(state.VerifiedChains[0][0].Raw))
+}
+
+// ForwardedUnaryServerInterceptor makes the certificate forwarded by the
+// HTTP gateway available to ClientCertificate. It trusts the metadata, so it
+// only belongs on the server the gateway reaches in memory, before the
+// interceptors that read the certificate.
+func ForwardedUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withForwardedCertificate(ctx), req)
+	}
+}
+
+// ForwardedStreamServerInterceptor is ForwardedUnaryServerInterceptor for
+// streaming calls.
+func ForwardedStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: stream, ctx: withForwardedCertificate(stream.Context())})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func withForwardedCertificate(ctx context.Context) context.Context {
+	values := metadata.ValueFromIncomingContext(ctx, FORWARDED_CERTIFICATE_METADATA)
+	if len(values) == 0 {
+		return ctx
+	}
+	cert, err := x509.ParseCertificate([]byte(values[0]))
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, forwardedKey{}, cert)
+}
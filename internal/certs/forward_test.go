@@ -0,0 +1,56 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// forwardedCertificate passes md through the forwarding interceptor and
+// returns the certificate the handler sees.
+func forwardedCertificate(t *testing.T, md metadata.MD) *x509.Certificate {
+	t.Helper()
+
+	var cert *x509.Certificate
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := ForwardedUnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		cert, _ = ClientCertificate(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return cert
+}
+
+func TestForwardClientCertificate(t *testing.T) {
+	client := newTestCA(t).cert
+	spoofed := newTestCA(t).cert
+
+	md := metadata.Pairs(FORWARDED_CERTIFICATE_METADATA, string(spoofed.Raw))
+	ForwardClientCertificate(md, &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{client}}})
+	if cert := forwardedCertificate(t, md); cert == nil || !cert.Equal(client) {
+		t.Fatalf("Expected the verified certificate, got %v", cert)
+	}
+
+	// A certificate sent by a client without a verified one is dropped.
+	md = metadata.Pairs(FORWARDED_CERTIFICATE_METADATA, string(spoofed.Raw))
+	ForwardClientCertificate(md, &tls.ConnectionState{})
+	if cert := forwardedCertificate(t, md); cert != nil {
+		t.Fatalf("Expected no certificate without a verified chain, got %v", cert.Subject)
+	}
+	ForwardClientCertificate(md, nil)
+	if cert := forwardedCertificate(t, md); cert != nil {
+		t.Fatalf("Expected no certificate without TLS, got %v", cert.Subject)
+	}
+
+	// Without the interceptor the metadata is ignored.
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(FORWARDED_CERTIFICATE_METADATA, string(spoofed.Raw)))
+	if _, ok := ClientCertificate(ctx); ok {
+		t.Fatalf("Expected no certificate without the interceptor")
+	}
+}
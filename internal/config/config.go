@@ -7,25 +7,157 @@ import (
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Report   ReportConfig
+	Cache    CacheConfig
+	Metrics  MetricsConfig
+	Tracing  TracingConfig
+	Logging  LoggingConfig
+	Health   HealthConfig
+	Auth     AuthConfig
 }
 
+// ServerConfig is where the gRPC server and the HTTP gateway listen.
+// ShutdownDrainDelay is how long requests are still accepted after health
+// turns NOT_SERVING on SIGTERM or SIGINT, and ShutdownTimeout how long running
+// requests may take to finish after that, both time.Duration strings.
 type ServerConfig struct {
-	Port string
-	Host string
+	Port               string
+	Host               string
+	HTTPPort           string
+	ShutdownDrainDelay string
+	ShutdownTimeout    string
+	TLS                TLSConfig
+}
+
+// TLSConfig holds PEM file paths. TLS is enabled when CertFile and KeyFile
+// are set, and with ClientCAFile clients must present a certificate signed
+// by one of its CAs.
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
 }
 
+// DatabaseConfig selects the store, DSN takes precedence over FilePath. See
+// database.Open for the supported schemes.
 type DatabaseConfig struct {
-	FilePath string
+	FilePath    string
+	DSN         string
+	AutoMigrate bool
+}
+
+func (c DatabaseConfig) DataSource() string {
+	if c.DSN != "" {
+		return c.DSN
+	}
+	return c.FilePath
+}
+
+type ReportConfig struct {
+	WeekStart string
+}
+
+// CacheConfig bounds the query result cache, TTL is a time.Duration string
+// and Size a number of rows.
+// A zero TTL or Size disables the cache.
+type CacheConfig struct {
+	TTL  string
+	Size string
+}
+
+// MetricsConfig is where /metrics is served in the Prometheus format.
+type MetricsConfig struct {
+	Port string
+}
+
+// TracingConfig selects where spans are exported: none, stdout or otlp.
+type TracingConfig struct {
+	Exporter string
+}
+
+// LoggingConfig is the lowest level that is logged: debug, info, warn or
+// error.
+type LoggingConfig struct {
+	Level string
+}
+
+// HealthConfig is how often the database is checked for the gRPC health
+// service, a time.Duration string, and the plaintext port it is also served
+// on for probes that cannot use TLS.
+type HealthConfig struct {
+	Interval string
+	Port     string
+}
+
+// AuthConfig enables bearer token authentication when JWTKeysFile or APIKeys
+// is set. JWTKeysFile holds PEM public keys, tokens must be signed by one of
+// them and issued by JWTIssuer, and for JWTAudience when it is set. APIKeys
+// is a comma separated list of name:role:key, agent keys are
+// name:agent:agent_id:key. Without either the server refuses to start, unless
+// Disabled opts out of authentication.
+type AuthConfig struct {
+	JWTIssuer     string
+	JWTAudience   string
+	JWTKeysFile   string
+	JWTRolesClaim string
+	APIKeys       string
+	Disabled      bool
+}
+
+func (c AuthConfig) Enabled() bool {
+	return c.JWTKeysFile != "" || c.APIKeys != ""
 }
 
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "50051"),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:               getEnv("SERVER_PORT", "50051"),
+			Host:               getEnv("SERVER_HOST", "0.0.0.0"),
+			HTTPPort:           getEnv("HTTP_PORT", "8080"),
+			ShutdownDrainDelay: getEnv("SHUTDOWN_DRAIN_DELAY", "5s"),
+			ShutdownTimeout:    getEnv("SHUTDOWN_TIMEOUT", "30s"),
+			TLS: TLSConfig{
+				CertFile:     getEnv("TLS_CERT_FILE", ""),
+				KeyFile:      getEnv("TLS_KEY_FILE", ""),
+				ClientCAFile: getEnv("TLS_CLIENT_CA_FILE", ""),
+			},
 		},
 		Database: DatabaseConfig{
-			FilePath: getEnv("DB_FILE_PATH", "./database.db"),
+			FilePath:    getEnv("DB_FILE_PATH", "./database.db"),
+			DSN:         getEnv("DB_DSN", ""),
+			AutoMigrate: getEnv("DB_AUTO_MIGRATE", "false") == "true",
+		},
+		Report: ReportConfig{
+			WeekStart: getEnv("REPORT_WEEK_START", "monday"),
+		},
+		Cache: CacheConfig{
+			TTL:  getEnv("CACHE_TTL", "1m"),
+			Size: getEnv("CACHE_SIZE", "100000"),
+		},
+		Metrics: MetricsConfig{
+			Port: getEnv("METRICS_PORT", "9090"),
+		},
+		Tracing: TracingConfig{
+			Exporter: getEnv("TRACING_EXPORTER", "none"),
+		},
+		Logging: LoggingConfig{
+			Level: getEnv("LOG_LEVEL", "info"),
+		},
+		Health: HealthConfig{
+			Interval: getEnv("HEALTH_CHECK_INTERVAL", "10s"),
+			Port:     getEnv("HEALTH_PORT", "50052"),
+		},
+		Auth: AuthConfig{
+			JWTIssuer:     getEnv("AUTH_JWT_ISSUER", ""),
+			JWTAudience:   getEnv("AUTH_JWT_AUDIENCE", ""),
+			JWTKeysFile:   getEnv("AUTH_JWT_KEYS_FILE", ""),
+			JWTRolesClaim: getEnv("AUTH_JWT_ROLES_CLAIM", "roles"),
+			APIKeys:       getEnv("AUTH_API_KEYS", ""),
+			Disabled:      getEnv("AUTH_DISABLED", "false") == "true",
 		},
 	}
 }
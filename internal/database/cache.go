@@ -0,0 +1,300 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CachedStore caches query results of another store for a fixed time. Every
+// write through the store drops the whole cache, as a new rating or weight
+// can change the result of any query. Writes that bypass the store, e.g. by
+// another replica, are only seen once the cached results expire.
+//
+// The cache is bounded by the rows it holds, a list of ratings costs one row
+// per rating and a score one row. A result with more rows than the whole
+// cache is returned without being kept.
+//
+// Cached ratings and categories are shared between callers and must not be
+// modified. StreamWeightedRatings is not cached, it is meant for ranges too
+// large to hold in memory.
+type CachedStore struct {
+	store          Store
+	cache          *queryCache
+	currentWeights bool
+}
+
+// CacheStats counts lookups since the cache was created.
+type CacheStats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	Entries       int
+}
+
+var _ Store = (*CachedStore)(nil)
+
+// NewCachedStore caches up to size rows of results of store, each for ttl.
+func NewCachedStore(store Store, ttl time.Duration, size int) *CachedStore {
+	return &CachedStore{store: store, cache: newQueryCache(ttl, size)}
+}
+
+func (c *CachedStore) WithCurrentWeights() Store {
+	return &CachedStore{store: c.store.WithCurrentWeights(), cache: c.cache, currentWeights: true}
+}
+
+func (c *CachedStore) Close() error {
+	return c.store.Close()
+}
+
+func (c *CachedStore) Stats() CacheStats {
+	return c.cache.stats()
+}
+
+func (c *CachedStore) GetOverallScore(startDate, endDate string) (float32, error) {
+	return cached(c, c.key("GetOverallScore", startDate, endDate), func() (float32, error) {
+		return c.store.GetOverallScore(startDate, endDate)
+	})
+}
+
+func (c *CachedStore) GetDailyScores(startDate, endDate string) ([]DailyScore, error) {
+	return cached(c, c.key("GetDailyScores", startDate, endDate), func() ([]DailyScore, error) {
+		return c.store.GetDailyScores(startDate, endDate)
+	})
+}
+
+func (c *CachedStore) GetWeightedRatings(startDate, endDate string) ([]Rating, error) {
+	return cached(c, c.key("GetWeightedRatings", startDate, endDate), func() ([]Rating, error) {
+		return c.store.GetWeightedRatings(startDate, endDate)
+	})
+}
+
+func (c *CachedStore) StreamWeightedRatings(ctx context.Context, startDate, endDate string, fn func(Rating) error) error {
+	return c.store.StreamWeightedRatings(ctx, startDate, endDate, fn)
+}
+
+func (c *CachedStore) GetAgentWeightedRatings(agentID int64, startDate, endDate string) ([]Rating, error) {
+	return cached(c, c.key("GetAgentWeightedRatings", agentID, startDate, endDate), func() ([]Rating, error) {
+		return c.store.GetAgentWeightedRatings(agentID, startDate, endDate)
+	})
+}
+
+func (c *CachedStore) GetRatedCategoryIDs(startDate, endDate string) ([]int64, error) {
+	return cached(c, c.key("GetRatedCategoryIDs", startDate, endDate), func() ([]int64, error) {
+		return c.store.GetRatedCategoryIDs(startDate, endDate)
+	})
+}
+
+func (c *CachedStore) GetAgentRatings(startDate, endDate string) ([]Rating, error) {
+	return cached(c, c.key("GetAgentRatings", startDate, endDate), func() ([]Rating, error) {
+		return c.store.GetAgentRatings(startDate, endDate)
+	})
+}
+
+func (c *CachedStore) GetReviewerRatings(startDate, endDate string) ([]Rating, error) {
+	return cached(c, c.key("GetReviewerRatings", startDate, endDate), func() ([]Rating, error) {
+		return c.store.GetReviewerRatings(startDate, endDate)
+	})
+}
+
+func (c *CachedStore) GetTicketRatings(startDate, endDate string, afterTicketID int64, limit int) ([]Rating, error) {
+	return cached(c, c.key("GetTicketRatings", startDate, endDate, afterTicketID, limit), func() ([]Rating, error) {
+		return c.store.GetTicketRatings(startDate, endDate, afterTicketID, limit)
+	})
+}
+
+func (c *CachedStore) InsertRating(rating Rating) (int64, error) {
+	defer c.cache.invalidate()
+	return c.store.InsertRating(rating)
+}
+
+func (c *CachedStore) InsertRatings(ratings []Rating) ([]int64, error) {
+	defer c.cache.invalidate()
+	return c.store.InsertRatings(ratings)
+}
+
+func (c *CachedStore) GetCategories() ([]Category, error) {
+	return cached(c, c.key("GetCategories"), c.store.GetCategories)
+}
+
+func (c *CachedStore) GetCategory(id int64) (Category, error) {
+	return cached(c, c.key("GetCategory", id), func() (Category, error) {
+		return c.store.GetCategory(id)
+	})
+}
+
+func (c *CachedStore) GetCategoryWeights(id int64) ([]CategoryWeight, error) {
+	return cached(c, c.key("GetCategoryWeights", id), func() ([]CategoryWeight, error) {
+		return c.store.GetCategoryWeights(id)
+	})
+}
+
+func (c *CachedStore) CreateCategory(name string, weight float64) (Category, error) {
+	defer c.cache.invalidate()
+	return c.store.CreateCategory(name, weight)
+}
+
+func (c *CachedStore) RenameCategory(id int64, name string) (Category, error) {
+	defer c.cache.invalidate()
+	return c.store.RenameCategory(id, name)
+}
+
+func (c *CachedStore) ReweightCategory(id int64, weight float64, effectiveFrom time.Time) (Category, error) {
+	defer c.cache.invalidate()
+	return c.store.ReweightCategory(id, weight, effectiveFrom)
+}
+
+func (c *CachedStore) RetireCategory(id int64, retiredAt time.Time) (Category, error) {
+	defer c.cache.invalidate()
+	return c.store.RetireCategory(id, retiredAt)
+}
+
+// key identifies a query by method, weights and arguments. The arguments are
+// already normalized, ranges are UTC timestamps in TIMESTAMP_FORMAT.
+func (c *CachedStore) key(method string, args ...any) string {
+	return fmt.Sprintf("%s|%t|%v", method, c.currentWeights, args)
+}
+
+// cached returns the cached result of key, or loads and caches it. Errors are
+// not cached.
+func cached[T any](c *CachedStore, key string, load func() (T, error)) (T, error) {
+	if value, ok := c.cache.get(key); ok {
+		return value.(T), nil
+	}
+
+	generation := c.cache.generation()
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	c.cache.put(key, value, resultRows(value), generation)
+	return value, nil
+}
+
+// resultRows is the number of rows of a query result, at least one.
+func resultRows(value any) int {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && v.Len() > 1 {
+		return v.Len()
+	}
+	return 1
+}
+
+// queryCache is a least recently used cache with expiring entries, holding
+// up to size rows.
+type queryCache struct {
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	rows    int
+	// gen is bumped on every invalidation, results loaded before it are not
+	// stored.
+	gen uint64
+
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	evictions     atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+type cacheEntry struct {
+	key     string
+	value   any
+	rows    int
+	expires time.Time
+}
+
+func newQueryCache(ttl time.Duration, size int) *queryCache {
+	return &queryCache{
+		ttl:     ttl,
+		size:    size,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (q *queryCache) get(key string) (any, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	element, ok := q.entries[key]
+	if !ok {
+		q.misses.Add(1)
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !q.now().Before(entry.expires) {
+		q.remove(element)
+		q.misses.Add(1)
+		return nil, false
+	}
+
+	q.order.MoveToFront(element)
+	q.hits.Add(1)
+	return entry.value, true
+}
+
+func (q *queryCache) put(key string, value any, rows int, generation uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if generation != q.gen || rows > q.size {
+		return
+	}
+	if element, ok := q.entries[key]; ok {
+		q.remove(element)
+	}
+
+	q.entries[key] = q.order.PushFront(&cacheEntry{key: key, value: value, rows: rows, expires: q.now().Add(q.ttl)})
+	q.rows += rows
+	for q.rows > q.size {
+		q.remove(q.order.Back())
+		q.evictions.Add(1)
+	}
+}
+
+func (q *queryCache) generation() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.gen
+}
+
+func (q *queryCache) invalidate() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.gen++
+	q.entries = make(map[string]*list.Element)
+	q.order.Init()
+	q.rows = 0
+	q.invalidations.Add(1)
+}
+
+func (q *queryCache) remove(element *list.Element) {
+	entry := q.order.Remove(element).(*cacheEntry)
+	delete(q.entries, entry.key)
+	q.rows -= entry.rows
+}
+
+func (q *queryCache) stats() CacheStats {
+	q.mu.Lock()
+	entries := q.order.Len()
+	q.mu.Unlock()
+
+	return CacheStats{
+		Hits:          q.hits.Load(),
+		Misses:        q.misses.Load(),
+		Evictions:     q.evictions.Load(),
+		Invalidations: q.invalidations.Load(),
+		Entries:       entries,
+	}
+}
@@ -0,0 +1,227 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCachedStore(t *testing.T) {
+	testStore(t, func(t *testing.T, fixture Fixture) Store {
+		store, err := NewMemoryStore(fixture)
+		if err != nil {
+			t.Fatalf("Failed to create memory store: %v", err)
+		}
+		return NewCachedStore(store, time.Minute, 100)
+	})
+}
+
+// countingStore counts the GetOverallScore calls that reach the store.
+type countingStore struct {
+	Store
+	calls int
+	err   error
+}
+
+func (s *countingStore) GetOverallScore(startDate, endDate string) (float32, error) {
+	s.calls++
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.Store.GetOverallScore(startDate, endDate)
+}
+
+func (s *countingStore) WithCurrentWeights() Store {
+	return &countingStore{Store: s.Store.WithCurrentWeights()}
+}
+
+func newCountingStore(t *testing.T) *countingStore {
+	t.Helper()
+
+	store, err := NewMemoryStore(loadFixture(t))
+	if err != nil {
+		t.Fatalf("Failed to create memory store: %v", err)
+	}
+	return &countingStore{Store: store}
+}
+
+func expectStats(t *testing.T, store *CachedStore, expected CacheStats) {
+	t.Helper()
+
+	if stats := store.Stats(); stats != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestCachedStoreHitsAndMisses(t *testing.T) {
+	counting := newCountingStore(t)
+	store := NewCachedStore(counting, time.Minute, 10)
+
+	for i := 0; i < 3; i++ {
+		if _, err := store.GetOverallScore(FIXTURE_START, FIXTURE_END); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if counting.calls != 1 {
+		t.Fatalf("Expected one query, got %d", counting.calls)
+	}
+	expectStats(t, store, CacheStats{Hits: 2, Misses: 1, Entries: 1})
+
+	// Other ranges and weights are cached separately.
+	if _, err := store.GetOverallScore(FIXTURE_START, "2025-01-02T23:59:59"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	historical, _ := store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+	current, err := store.WithCurrentWeights().GetOverallScore(FIXTURE_START, FIXTURE_END)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if historical == current {
+		t.Fatalf("Expected current weights to be cached separately, got %v for both", current)
+	}
+	expectStats(t, store, CacheStats{Hits: 3, Misses: 3, Entries: 3})
+}
+
+func TestCachedStoreExpires(t *testing.T) {
+	counting := newCountingStore(t)
+	store := NewCachedStore(counting, time.Minute, 10)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.cache.now = func() time.Time { return now }
+
+	store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+	now = now.Add(59 * time.Second)
+	store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+	if counting.calls != 1 {
+		t.Fatalf("Expected the result to be cached, got %d queries", counting.calls)
+	}
+
+	now = now.Add(time.Second)
+	store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+	if counting.calls != 2 {
+		t.Fatalf("Expected the result to expire, got %d queries", counting.calls)
+	}
+	expectStats(t, store, CacheStats{Hits: 1, Misses: 2, Entries: 1})
+}
+
+func TestCachedStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewCachedStore(newCountingStore(t), time.Minute, 2)
+
+	store.GetOverallScore(FIXTURE_START, "2025-01-01T23:59:59")
+	store.GetOverallScore(FIXTURE_START, "2025-01-02T23:59:59")
+	store.GetOverallScore(FIXTURE_START, "2025-01-01T23:59:59")
+	store.GetOverallScore(FIXTURE_START, "2025-01-03T23:59:59")
+	expectStats(t, store, CacheStats{Hits: 1, Misses: 3, Evictions: 1, Entries: 2})
+
+	// The first range was used more recently than the second one.
+	store.GetOverallScore(FIXTURE_START, "2025-01-01T23:59:59")
+	store.GetOverallScore(FIXTURE_START, "2025-01-02T23:59:59")
+	expectStats(t, store, CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2})
+}
+
+func TestCachedStoreInvalidatesOnWrite(t *testing.T) {
+	store := NewCachedStore(newCountingStore(t), time.Minute, 100)
+
+	before, err := store.GetWeightedRatings(FIXTURE_START, FIXTURE_END)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	store.GetCategories()
+
+	if _, err := store.InsertRating(Rating{TicketID: 1, CategoryID: 1, ReviewerID: 1, RevieweeID: 3, Value: 5, CreatedAt: date(3, 9, 0)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectStats(t, store, CacheStats{Misses: 2, Invalidations: 1})
+
+	after, err := store.GetWeightedRatings(FIXTURE_START, FIXTURE_END)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(after) != len(before)+1 {
+		t.Fatalf("Expected the new rating, got %d ratings", len(after))
+	}
+
+	if _, err := store.WithCurrentWeights().(*CachedStore).RenameCategory(1, "Orthography"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	categories, err := store.GetCategories()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if categories[0].Name != "Orthography" {
+		t.Fatalf("Expected the renamed category, got %v", categories[0])
+	}
+	expectStats(t, store, CacheStats{Misses: 4, Invalidations: 2, Entries: 1})
+}
+
+func TestCachedStoreSkipsErrorsAndStaleResults(t *testing.T) {
+	counting := newCountingStore(t)
+	counting.err = errors.New("database is locked")
+	store := NewCachedStore(counting, time.Minute, 10)
+
+	if _, err := store.GetOverallScore(FIXTURE_START, FIXTURE_END); err == nil {
+		t.Fatal("Expected an error")
+	}
+	counting.err = nil
+	if _, err := store.GetOverallScore(FIXTURE_START, FIXTURE_END); err != nil {
+		t.Fatalf("Expected errors not to be cached, got %v", err)
+	}
+
+	// A result loaded before an invalidation is not stored.
+	generation := store.cache.generation()
+	store.cache.invalidate()
+	store.cache.put("stale", float32(1), 1, generation)
+	if _, ok := store.cache.get("stale"); ok {
+		t.Fatal("Expected the stale result to be dropped")
+	}
+}
+
+func TestCachedStoreBoundsRows(t *testing.T) {
+	fixture := loadFixture(t)
+	store := NewCachedStore(newCountingStore(t), time.Minute, len(fixture.Ratings))
+
+	store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+	ratings, err := store.GetWeightedRatings(FIXTURE_START, FIXTURE_END)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ratings) != len(fixture.Ratings) {
+		t.Fatalf("Expected %d ratings, got %d", len(fixture.Ratings), len(ratings))
+	}
+	// The ratings fill the cache, the score is evicted to make room.
+	expectStats(t, store, CacheStats{Misses: 2, Evictions: 1, Entries: 1})
+	if store.cache.rows != len(fixture.Ratings) {
+		t.Fatalf("Expected %d cached rows, got %d", len(fixture.Ratings), store.cache.rows)
+	}
+
+	if _, err := store.InsertRating(Rating{TicketID: 1, CategoryID: 1, ReviewerID: 1, RevieweeID: 3, Value: 5, CreatedAt: date(3, 9, 0)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+
+	// One more rating than the cache holds is not kept, and does not evict
+	// the smaller results.
+	for i := 0; i < 2; i++ {
+		ratings, err := store.GetWeightedRatings(FIXTURE_START, FIXTURE_END)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(ratings) != len(fixture.Ratings)+1 {
+			t.Fatalf("Expected %d ratings, got %d", len(fixture.Ratings)+1, len(ratings))
+		}
+	}
+	expectStats(t, store, CacheStats{Misses: 5, Evictions: 1, Invalidations: 1, Entries: 1})
+	if store.cache.rows != 1 {
+		t.Fatalf("Expected only the score to be cached, got %d rows", store.cache.rows)
+	}
+}
+
+func TestCachedStoreDisabled(t *testing.T) {
+	counting := newCountingStore(t)
+	store := NewCachedStore(counting, time.Minute, 0)
+
+	store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+	store.GetOverallScore(FIXTURE_START, FIXTURE_END)
+	if counting.calls != 2 {
+		t.Fatalf("Expected every call to reach the store, got %d queries", counting.calls)
+	}
+}
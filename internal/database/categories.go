@@ -0,0 +1,255 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrRetired       = errors.New("category is retired")
+)
+
+// INITIAL_WEIGHT_EFFECTIVE_FROM is the effective date of the first weight of
+// every category, so it applies to all ratings created before a re-weight.
+const INITIAL_WEIGHT_EFFECTIVE_FROM = "0001-01-01T00:00:00"
+
+type Category struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Weight    float64    `json:"weight"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+func (c Category) Retired() bool {
+	return c.RetiredAt != nil
+}
+
+type CategoryWeight struct {
+	CategoryID    int64     `json:"category_id"`
+	Weight        float64   `json:"weight"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+func (r *Repository) GetCategories() ([]Category, error) {
+	rows, err := r.query(`SELECT id, name, weight, retired_at FROM rating_categories ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, rows.Err()
+}
+
+func (r *Repository) GetCategory(id int64) (Category, error) {
+	return getCategory(r.db, r.dialect, id)
+}
+
+func (r *Repository) GetCategoryWeights(id int64) ([]CategoryWeight, error) {
+	rows, err := r.query(`
+		SELECT rating_category_id, weight, effective_from
+		FROM rating_category_weights
+		WHERE rating_category_id = ?
+		ORDER BY effective_from, id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var weights []CategoryWeight
+	for rows.Next() {
+		var weight CategoryWeight
+		var effectiveFrom any
+		if err := rows.Scan(&weight.CategoryID, &weight.Weight, &effectiveFrom); err != nil {
+			return nil, err
+		}
+		if weight.EffectiveFrom, err = parseTimestamp(effectiveFrom); err != nil {
+			return nil, err
+		}
+		weights = append(weights, weight)
+	}
+
+	return weights, rows.Err()
+}
+
+func (r *Repository) CreateCategory(name string, weight float64) (Category, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return Category{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkCategoryName(tx, r.dialect, name, 0); err != nil {
+		return Category{}, err
+	}
+
+	var id int64
+	err = tx.QueryRow(r.dialect.rebind(`INSERT INTO rating_categories (name, weight) VALUES (?, ?) RETURNING id`), name, weight).Scan(&id)
+	if err != nil {
+		return Category{}, err
+	}
+
+	_, err = tx.Exec(r.dialect.rebind(`INSERT INTO rating_category_weights (rating_category_id, weight, effective_from) VALUES (?, ?, ?)`),
+		id, weight, INITIAL_WEIGHT_EFFECTIVE_FROM)
+	if err != nil {
+		return Category{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Category{}, err
+	}
+	return Category{ID: id, Name: name, Weight: weight}, nil
+}
+
+func (r *Repository) RenameCategory(id int64, name string) (Category, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return Category{}, err
+	}
+	defer tx.Rollback()
+
+	if err := checkCategoryName(tx, r.dialect, name, id); err != nil {
+		return Category{}, err
+	}
+
+	if err := execCategoryUpdate(tx, r.dialect.rebind(`UPDATE rating_categories SET name = ? WHERE id = ?`), name, id); err != nil {
+		return Category{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Category{}, err
+	}
+	return r.GetCategory(id)
+}
+
+// ReweightCategory records a new weight effective from the given time.
+// Ratings created before that keep being scored with the previous weight,
+// the rollups from that day on are rebuilt.
+func (r *Repository) ReweightCategory(id int64, weight float64, effectiveFrom time.Time) (Category, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return Category{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := getCategory(tx, r.dialect, id); err != nil {
+		return Category{}, err
+	}
+
+	effective := effectiveFrom.UTC().Format(TIMESTAMP_FORMAT)
+	_, err = tx.Exec(r.dialect.rebind(`INSERT INTO rating_category_weights (rating_category_id, weight, effective_from) VALUES (?, ?, ?)`),
+		id, weight, effective)
+	if err != nil {
+		return Category{}, err
+	}
+
+	// The current weight only changes when no later weight is already recorded.
+	err = execCategoryUpdate(tx, r.dialect.rebind(`
+		UPDATE rating_categories SET weight = ?
+		WHERE id = ? AND NOT EXISTS (
+			SELECT 1 FROM rating_category_weights
+			WHERE rating_category_id = rating_categories.id AND effective_from > ?)`),
+		weight, id, effective)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return Category{}, err
+	}
+
+	if err := rebuildCategoryRollups(tx, r.dialect, id, effectiveFrom); err != nil {
+		return Category{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Category{}, err
+	}
+	return r.GetCategory(id)
+}
+
+func (r *Repository) RetireCategory(id int64, retiredAt time.Time) (Category, error) {
+	err := execCategoryUpdate(r.db, r.dialect.rebind(`UPDATE rating_categories SET retired_at = ? WHERE id = ? AND retired_at IS NULL`),
+		retiredAt.UTC().Format(TIMESTAMP_FORMAT), id)
+	if errors.Is(err, ErrNotFound) {
+		category, getErr := r.GetCategory(id)
+		if getErr != nil {
+			return Category{}, getErr
+		}
+		return category, ErrRetired
+	}
+	if err != nil {
+		return Category{}, err
+	}
+	return r.GetCategory(id)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func scanCategory(row scanner) (Category, error) {
+	var category Category
+	var retiredAt any
+	if err := row.Scan(&category.ID, &category.Name, &category.Weight, &retiredAt); err != nil {
+		return Category{}, err
+	}
+	if retiredAt != nil {
+		t, err := parseTimestamp(retiredAt)
+		if err != nil {
+			return Category{}, err
+		}
+		category.RetiredAt = &t
+	}
+	return category, nil
+}
+
+func getCategory(db queryRower, d dialect, id int64) (Category, error) {
+	category, err := scanCategory(db.QueryRow(d.rebind(`SELECT id, name, weight, retired_at FROM rating_categories WHERE id = ?`), id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Category{}, ErrNotFound
+	}
+	return category, err
+}
+
+func checkCategoryName(tx *sql.Tx, d dialect, name string, exceptID int64) error {
+	var count int
+	err := tx.QueryRow(d.rebind(`SELECT COUNT(*) FROM rating_categories WHERE name = ? AND id != ?`), name, exceptID).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAlreadyExists
+	}
+	return nil
+}
+
+func execCategoryUpdate(db execer, query string, args ...any) error {
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
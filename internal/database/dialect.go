@@ -0,0 +1,72 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+type dialect struct {
+	name   string
+	driver string
+	// day formats the timestamp or date column given as %s as a DAY_FORMAT
+	// string, date converts it to the type of rating_daily_rollups.day.
+	day  string
+	date string
+	// numbered dialects take $1, $2, ... instead of ? placeholders.
+	numbered bool
+	// tableExists counts the tables with the name given as its only argument.
+	tableExists string
+	// recordVersion and deleteVersion change schema_migrations.
+	recordVersion string
+	deleteVersion string
+}
+
+var (
+	sqliteDialect = dialect{
+		name:          "sqlite",
+		driver:        "sqlite3",
+		day:           "DATE(%s)",
+		date:          "DATE(%s)",
+		tableExists:   `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`,
+		recordVersion: `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		deleteVersion: `DELETE FROM schema_migrations WHERE version = ?`,
+	}
+	postgresDialect = dialect{
+		name:          "postgres",
+		driver:        "pgx",
+		day:           "TO_CHAR(%s, 'YYYY-MM-DD')",
+		date:          "CAST(%s AS DATE)",
+		numbered:      true,
+		tableExists:   `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1`,
+		recordVersion: `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+		deleteVersion: `DELETE FROM schema_migrations WHERE version = $1`,
+	}
+)
+
+func (d dialect) dayOf(column string) string {
+	return fmt.Sprintf(d.day, column)
+}
+
+func (d dialect) dateOf(column string) string {
+	return fmt.Sprintf(d.date, column)
+}
+
+// rebind replaces the ? placeholders of query with the ones of the dialect.
+// Queries are written once, with ? and no literal question marks.
+func (d dialect) rebind(query string) string {
+	if !d.numbered {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
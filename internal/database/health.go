@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// HealthChecker is a store backed by a database that can become unavailable
+// while the server runs.
+type HealthChecker interface {
+	// CheckHealth returns an error unless the database answers, is at the
+	// schema version the code expects and its tables can be read.
+	CheckHealth(ctx context.Context) error
+}
+
+var _ HealthChecker = (*Repository)(nil)
+
+// healthTables are the tables the store reads. SQLite creates a missing
+// database file on connect, so an empty database is only told apart by its
+// schema.
+var healthTables = []string{
+	"users",
+	"tickets",
+	"rating_categories",
+	"ratings",
+	"rating_category_weights",
+	"rating_daily_rollups",
+}
+
+func (r *Repository) CheckHealth(ctx context.Context) error {
+	return checkHealth(ctx, r.db, r.dialect)
+}
+
+// checkHealth reads the schema version and a row of every table, without
+// changing the database the way the Migrator does.
+func checkHealth(ctx context.Context, db *sql.DB, d dialect) error {
+	if err := db.PingContext(ctx); err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations(d.name)
+	if err != nil {
+		return err
+	}
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read the schema version: %w", err)
+	}
+	if version != len(migrations) {
+		return fmt.Errorf("%w: database is at version %d, expected %d", ErrSchemaVersion, version, len(migrations))
+	}
+
+	for _, table := range healthTables {
+		var one int
+		err := db.QueryRowContext(ctx, `SELECT 1 FROM `+table+` LIMIT 1`).Scan(&one)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to read %s: %w", table, err)
+		}
+	}
+	return nil
+}
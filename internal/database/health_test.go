@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteCheckHealth(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "database.db")
+
+	repo, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	if err := repo.CheckHealth(ctx); err == nil {
+		t.Fatalf("Expected an error for a database without a schema")
+	}
+
+	migrator := newTestMigrator(t, path)
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err := repo.CheckHealth(ctx); err != nil {
+		t.Fatalf("Expected a healthy database, got %v", err)
+	}
+
+	if _, err := migrator.Down(false); err != nil {
+		t.Fatalf("Failed to revert: %v", err)
+	}
+	if err := repo.CheckHealth(ctx); !errors.Is(err, ErrSchemaVersion) {
+		t.Fatalf("Expected ErrSchemaVersion, got %v", err)
+	}
+
+	repo.Close()
+	if err := repo.CheckHealth(ctx); err == nil {
+		t.Fatalf("Expected an error for a closed repository")
+	}
+}
+
+func TestSQLiteCheckHealthCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+	if err := os.WriteFile(path, []byte("not a database, but long enough to not be taken for an empty one"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	repo, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+	if err := repo.CheckHealth(context.Background()); err == nil {
+		t.Fatalf("Expected an error for a corrupt database")
+	}
+}
+
+func TestPostgresCheckHealth(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	repo := openPostgres(t, dsn, loadFixture(t)).(*Repository)
+	if err := repo.CheckHealth(context.Background()); err != nil {
+		t.Fatalf("Expected a healthy database, got %v", err)
+	}
+}
@@ -0,0 +1,484 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+type User struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Fixture is the data a MemoryStore starts with. Categories without any
+// weight get their current weight as the initial one, and ratings without an
+// id are numbered after the highest given id. Ids must be unique, as the
+// primary keys of the tables require, and weights and ratings must refer to
+// one of the categories.
+type Fixture struct {
+	Users      []User           `json:"users"`
+	Categories []Category       `json:"categories"`
+	Weights    []CategoryWeight `json:"weights"`
+	Ratings    []Rating         `json:"ratings"`
+}
+
+// MemoryStore answers the same queries as Repository from data kept in
+// memory, so the service can run without a database file.
+type MemoryStore struct {
+	data           *memoryData
+	currentWeights bool
+}
+
+// memoryData is shared by a store and its WithCurrentWeights copies. Initial
+// weights are effective from the zero time, INITIAL_WEIGHT_EFFECTIVE_FROM.
+type memoryData struct {
+	mu           sync.RWMutex
+	users        map[int64]string
+	categories   []Category
+	weights      []CategoryWeight
+	ratings      []Rating
+	lastRatingID int64
+}
+
+func NewMemoryStore(fixture Fixture) (*MemoryStore, error) {
+	data := &memoryData{users: make(map[int64]string)}
+	for _, user := range fixture.Users {
+		if _, ok := data.users[user.ID]; ok {
+			return nil, fmt.Errorf("duplicate user id %d", user.ID)
+		}
+		data.users[user.ID] = user.Name
+	}
+
+	names := make(map[string]bool)
+	weighted := make(map[int64]bool)
+	for _, weight := range fixture.Weights {
+		weight.EffectiveFrom = storedTime(weight.EffectiveFrom)
+		data.weights = append(data.weights, weight)
+		weighted[weight.CategoryID] = true
+	}
+	for _, category := range fixture.Categories {
+		if _, ok := data.category(category.ID); ok {
+			return nil, fmt.Errorf("duplicate category id %d", category.ID)
+		}
+		if names[category.Name] {
+			return nil, fmt.Errorf("duplicate category name %q", category.Name)
+		}
+		names[category.Name] = true
+
+		if category.RetiredAt != nil {
+			retiredAt := storedTime(*category.RetiredAt)
+			category.RetiredAt = &retiredAt
+		}
+		data.categories = append(data.categories, category)
+
+		if !weighted[category.ID] {
+			data.weights = append(data.weights, CategoryWeight{CategoryID: category.ID, Weight: category.Weight})
+		}
+	}
+	sort.Slice(data.categories, func(i, j int) bool { return data.categories[i].ID < data.categories[j].ID })
+	for _, weight := range fixture.Weights {
+		if _, ok := data.category(weight.CategoryID); !ok {
+			return nil, fmt.Errorf("weight has unknown category id %d", weight.CategoryID)
+		}
+	}
+
+	ratingIDs := make(map[int64]bool)
+	for _, rating := range fixture.Ratings {
+		if rating.ID == 0 {
+			continue
+		}
+		if ratingIDs[rating.ID] {
+			return nil, fmt.Errorf("duplicate rating id %d", rating.ID)
+		}
+		ratingIDs[rating.ID] = true
+		data.lastRatingID = max(data.lastRatingID, rating.ID)
+	}
+	for _, rating := range fixture.Ratings {
+		if _, ok := data.category(rating.CategoryID); !ok {
+			return nil, fmt.Errorf("rating %d has unknown category id %d", rating.ID, rating.CategoryID)
+		}
+		data.insert(rating)
+	}
+	sort.SliceStable(data.ratings, func(i, j int) bool { return data.ratings[i].ID < data.ratings[j].ID })
+
+	return &MemoryStore{data: data}, nil
+}
+
+// LoadMemoryStore reads a Fixture from a JSON file.
+func LoadMemoryStore(path string) (*MemoryStore, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return NewMemoryStore(fixture)
+}
+
+func (s *MemoryStore) WithCurrentWeights() Store {
+	c := *s
+	c.currentWeights = true
+	return &c
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func (s *MemoryStore) GetOverallScore(startDate, endDate string) (float32, error) {
+	scores, err := s.GetDailyScores(startDate, endDate)
+	if err != nil {
+		return 0, err
+	}
+	return overallScore(scores), nil
+}
+
+// GetDailyScores rolls up the ratings on every call, the memory store keeps
+// no rollups.
+func (s *MemoryStore) GetDailyScores(startDate, endDate string) ([]DailyScore, error) {
+	ratings, err := s.ratingsBetween(startDate, endDate, nil)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateDailyScores(ratings), nil
+}
+
+func (s *MemoryStore) GetWeightedRatings(startDate, endDate string) ([]Rating, error) {
+	return s.weightedRatings(startDate, endDate, nil)
+}
+
+func (s *MemoryStore) StreamWeightedRatings(ctx context.Context, startDate, endDate string, fn func(Rating) error) error {
+	ratings, err := s.weightedRatings(startDate, endDate, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, rating := range ratings {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(rating); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetAgentWeightedRatings(agentID int64, startDate, endDate string) ([]Rating, error) {
+	return s.weightedRatings(startDate, endDate, func(r Rating) bool { return r.RevieweeID == agentID })
+}
+
+func (s *MemoryStore) weightedRatings(startDate, endDate string, keep func(Rating) bool) ([]Rating, error) {
+	ratings, err := s.ratingsBetween(startDate, endDate, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	sortRatings(ratings, func(r Rating) int64 { return r.CreatedAt.Unix() })
+	for i, r := range ratings {
+		ratings[i] = Rating{Day: r.Day, CategoryID: r.CategoryID, Category: r.Category, Value: r.Value, Weight: r.Weight, CreatedAt: r.CreatedAt}
+	}
+	return ratings, nil
+}
+
+func (s *MemoryStore) GetRatedCategoryIDs(startDate, endDate string) ([]int64, error) {
+	ratings, err := s.ratingsBetween(startDate, endDate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool)
+	var ids []int64
+	for _, rating := range ratings {
+		if !seen[rating.CategoryID] {
+			seen[rating.CategoryID] = true
+			ids = append(ids, rating.CategoryID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (s *MemoryStore) GetAgentRatings(startDate, endDate string) ([]Rating, error) {
+	ratings, err := s.ratingsBetween(startDate, endDate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sortRatings(ratings, func(r Rating) int64 { return r.RevieweeID })
+	for i, r := range ratings {
+		ratings[i] = Rating{RevieweeID: r.RevieweeID, Reviewee: r.Reviewee, CategoryID: r.CategoryID, Category: r.Category, Value: r.Value, Weight: r.Weight}
+	}
+	return ratings, nil
+}
+
+func (s *MemoryStore) GetReviewerRatings(startDate, endDate string) ([]Rating, error) {
+	ratings, err := s.ratingsBetween(startDate, endDate, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sortRatings(ratings, func(r Rating) int64 { return r.ReviewerID })
+	for i, r := range ratings {
+		ratings[i] = Rating{ReviewerID: r.ReviewerID, Reviewer: r.Reviewer, CategoryID: r.CategoryID, Category: r.Category, Value: r.Value, Weight: r.Weight}
+	}
+	return ratings, nil
+}
+
+func (s *MemoryStore) GetTicketRatings(startDate, endDate string, afterTicketID int64, limit int) ([]Rating, error) {
+	ratings, err := s.ratingsBetween(startDate, endDate, func(r Rating) bool { return r.TicketID > afterTicketID })
+	if err != nil {
+		return nil, err
+	}
+
+	sortRatings(ratings, func(r Rating) int64 { return r.TicketID })
+
+	var result []Rating
+	tickets := 0
+	for i, r := range ratings {
+		if i == 0 || r.TicketID != ratings[i-1].TicketID {
+			if tickets == limit {
+				break
+			}
+			tickets++
+		}
+		result = append(result, Rating{TicketID: r.TicketID, Day: r.Day, CategoryID: r.CategoryID, Category: r.Category, Value: r.Value, Weight: r.Weight})
+	}
+	return result, nil
+}
+
+func (s *MemoryStore) InsertRating(rating Rating) (int64, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	rating.ID = 0
+	return s.data.insert(rating), nil
+}
+
+func (s *MemoryStore) InsertRatings(ratings []Rating) ([]int64, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	ids := make([]int64, 0, len(ratings))
+	for _, rating := range ratings {
+		rating.ID = 0
+		ids = append(ids, s.data.insert(rating))
+	}
+	return ids, nil
+}
+
+func (s *MemoryStore) GetCategories() ([]Category, error) {
+	s.data.mu.RLock()
+	defer s.data.mu.RUnlock()
+
+	return append([]Category(nil), s.data.categories...), nil
+}
+
+func (s *MemoryStore) GetCategory(id int64) (Category, error) {
+	s.data.mu.RLock()
+	defer s.data.mu.RUnlock()
+
+	category, ok := s.data.category(id)
+	if !ok {
+		return Category{}, ErrNotFound
+	}
+	return *category, nil
+}
+
+func (s *MemoryStore) GetCategoryWeights(id int64) ([]CategoryWeight, error) {
+	s.data.mu.RLock()
+	defer s.data.mu.RUnlock()
+
+	var weights []CategoryWeight
+	for _, weight := range s.data.weights {
+		if weight.CategoryID == id {
+			weights = append(weights, weight)
+		}
+	}
+	sort.SliceStable(weights, func(i, j int) bool { return weights[i].EffectiveFrom.Before(weights[j].EffectiveFrom) })
+	return weights, nil
+}
+
+func (s *MemoryStore) CreateCategory(name string, weight float64) (Category, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	if s.data.nameTaken(name, 0) {
+		return Category{}, ErrAlreadyExists
+	}
+
+	var id int64 = 1
+	if n := len(s.data.categories); n > 0 {
+		id = s.data.categories[n-1].ID + 1
+	}
+
+	category := Category{ID: id, Name: name, Weight: weight}
+	s.data.categories = append(s.data.categories, category)
+	s.data.weights = append(s.data.weights, CategoryWeight{CategoryID: id, Weight: weight})
+	return category, nil
+}
+
+func (s *MemoryStore) RenameCategory(id int64, name string) (Category, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	if s.data.nameTaken(name, id) {
+		return Category{}, ErrAlreadyExists
+	}
+
+	category, ok := s.data.category(id)
+	if !ok {
+		return Category{}, ErrNotFound
+	}
+	category.Name = name
+	return *category, nil
+}
+
+func (s *MemoryStore) ReweightCategory(id int64, weight float64, effectiveFrom time.Time) (Category, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	category, ok := s.data.category(id)
+	if !ok {
+		return Category{}, ErrNotFound
+	}
+
+	effective := storedTime(effectiveFrom)
+	latest := true
+	for _, w := range s.data.weights {
+		if w.CategoryID == id && w.EffectiveFrom.After(effective) {
+			latest = false
+		}
+	}
+
+	s.data.weights = append(s.data.weights, CategoryWeight{CategoryID: id, Weight: weight, EffectiveFrom: effective})
+	if latest {
+		category.Weight = weight
+	}
+	return *category, nil
+}
+
+func (s *MemoryStore) RetireCategory(id int64, retiredAt time.Time) (Category, error) {
+	s.data.mu.Lock()
+	defer s.data.mu.Unlock()
+
+	category, ok := s.data.category(id)
+	if !ok {
+		return Category{}, ErrNotFound
+	}
+	if category.Retired() {
+		return *category, ErrRetired
+	}
+
+	at := storedTime(retiredAt)
+	category.RetiredAt = &at
+	return *category, nil
+}
+
+// ratingsBetween returns the ratings created in the range that keep accepts,
+// with their category, weight and user names filled in.
+func (s *MemoryStore) ratingsBetween(startDate, endDate string, keep func(Rating) bool) ([]Rating, error) {
+	start, err := time.Parse(TIMESTAMP_FORMAT, startDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(TIMESTAMP_FORMAT, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	s.data.mu.RLock()
+	defer s.data.mu.RUnlock()
+
+	var ratings []Rating
+	for _, rating := range s.data.ratings {
+		if rating.CreatedAt.Before(start) || rating.CreatedAt.After(end) || (keep != nil && !keep(rating)) {
+			continue
+		}
+		category, ok := s.data.category(rating.CategoryID)
+		if !ok {
+			continue
+		}
+
+		rating.Category = category.Name
+		rating.Weight = s.weight(rating, *category)
+		rating.Day = rating.CreatedAt.Format(DAY_FORMAT)
+		rating.Reviewer = s.data.users[rating.ReviewerID]
+		rating.Reviewee = s.data.users[rating.RevieweeID]
+		ratings = append(ratings, rating)
+	}
+	return ratings, nil
+}
+
+// weight returns the weight the rating is scored with, the latest one in
+// effect when it was created unless current weights are requested.
+func (s *MemoryStore) weight(rating Rating, category Category) float64 {
+	if s.currentWeights {
+		return category.Weight
+	}
+
+	weight, found := category.Weight, false
+	var effectiveFrom time.Time
+	for _, w := range s.data.weights {
+		if w.CategoryID != category.ID || w.EffectiveFrom.After(rating.CreatedAt) {
+			continue
+		}
+		if !found || !w.EffectiveFrom.Before(effectiveFrom) {
+			weight, effectiveFrom, found = w.Weight, w.EffectiveFrom, true
+		}
+	}
+	return weight
+}
+
+// sortRatings orders ratings by key and category id, keeping insertion order
+// otherwise.
+func sortRatings(ratings []Rating, key func(Rating) int64) {
+	sort.SliceStable(ratings, func(i, j int) bool {
+		if key(ratings[i]) != key(ratings[j]) {
+			return key(ratings[i]) < key(ratings[j])
+		}
+		return ratings[i].CategoryID < ratings[j].CategoryID
+	})
+}
+
+func (d *memoryData) insert(rating Rating) int64 {
+	if rating.ID == 0 {
+		d.lastRatingID++
+		rating.ID = d.lastRatingID
+	}
+	rating.CreatedAt = storedTime(rating.CreatedAt)
+	d.ratings = append(d.ratings, rating)
+	return rating.ID
+}
+
+func (d *memoryData) category(id int64) (*Category, bool) {
+	for i := range d.categories {
+		if d.categories[i].ID == id {
+			return &d.categories[i], true
+		}
+	}
+	return nil, false
+}
+
+func (d *memoryData) nameTaken(name string, exceptID int64) bool {
+	for _, category := range d.categories {
+		if category.Name == name && category.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// storedTime drops what TIMESTAMP_FORMAT does not keep, so timestamps compare
+// the same way they do in the database.
+func storedTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
@@ -0,0 +1,329 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+var (
+	ErrSchemaVersion  = errors.New("schema version mismatch")
+	ErrNoMigrations   = errors.New("store has no schema to migrate")
+	ErrNoVersionTable = errors.New("no schema_migrations table")
+	ErrDropsData      = errors.New("reverting the initial migration drops every table with its data")
+)
+
+// Migration is one versioned schema change, read from
+// migrations/<dialect>/<version>_<name>.up.sql and the matching .down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+func (s MigrationStatus) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+// Migrator applies the embedded migrations and records them in the
+// schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	dialect    dialect
+	migrations []Migration
+}
+
+// NewMigrator opens the database of dsn the same way Open does. It returns
+// ErrNoMigrations for the in-memory store.
+func NewMigrator(dsn string) (*Migrator, error) {
+	var d dialect
+	scheme, source := parseDSN(dsn)
+	switch scheme {
+	case "postgres":
+		d = postgresDialect
+	case "sqlite":
+		d = sqliteDialect
+		source = sqliteDSN(source)
+	case "memory":
+		return nil, ErrNoMigrations
+	default:
+		return nil, fmt.Errorf("unsupported database scheme %q", scheme)
+	}
+
+	migrations, err := loadMigrations(d.name)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(d.driver, source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: d, migrations: migrations}, nil
+}
+
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name, direction, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), ".")
+		version, err := strconv.Atoi(strings.SplitN(name, "_", 2)[0])
+		if !ok || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			_, title, _ := strings.Cut(name, "_")
+			migration = &Migration{Version: version, Name: title}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %d needs both an up and a down file", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration %d is missing", i+1)
+		}
+	}
+	return migrations, nil
+}
+
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Latest is the version the code expects.
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Version is the version of the database, 0 when no migration is applied.
+// It returns ErrNoVersionTable for a database that Up has not run on yet.
+// Version, Check and Status only read the database.
+func (m *Migrator) Version() (int, error) {
+	exists, err := m.tableExists("schema_migrations")
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, ErrNoVersionTable
+	}
+
+	var version int
+	err = m.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+// Check returns ErrSchemaVersion unless the database is at the latest version.
+func (m *Migrator) Check() error {
+	version, err := m.Version()
+	if errors.Is(err, ErrNoVersionTable) {
+		return fmt.Errorf("%w: %w", ErrSchemaVersion, err)
+	}
+	if err != nil {
+		return err
+	}
+	if version != m.Latest() {
+		return fmt.Errorf("%w: database is at version %d, expected %d", ErrSchemaVersion, version, m.Latest())
+	}
+	return nil
+}
+
+// Status lists every migration with the time it was applied. It returns
+// ErrNoVersionTable for a database that Up has not run on yet.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	exists, err := m.tableExists("schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNoVersionTable
+	}
+
+	rows, err := m.db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt any
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		if applied[version], err = parseTimestamp(appliedAt); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up applies every pending migration, each in its own transaction, and
+// returns the applied ones.
+func (m *Migrator) Up() ([]Migration, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return nil, err
+	}
+
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	if version > m.Latest() {
+		return nil, fmt.Errorf("%w: database is at version %d, newer than %d", ErrSchemaVersion, version, m.Latest())
+	}
+
+	var applied []Migration
+	for _, migration := range m.migrations[version:] {
+		if err := m.apply(migration.up, m.dialect.recordVersion, migration.Version, migration.Name, time.Now().UTC().Format(TIMESTAMP_FORMAT)); err != nil {
+			return applied, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// Down reverts the latest applied migration and returns it, or nil when
+// nothing is recorded as applied. Reverting the initial migration drops the
+// ratings, categories, tickets and users, also of a legacy database that Up
+// only adopted, so it returns ErrDropsData unless dropData is set.
+func (m *Migrator) Down(dropData bool) (*Migration, error) {
+	version, err := m.Version()
+	if errors.Is(err, ErrNoVersionTable) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		return nil, nil
+	}
+	if version > m.Latest() {
+		return nil, fmt.Errorf("%w: database is at version %d, newer than %d", ErrSchemaVersion, version, m.Latest())
+	}
+
+	migration := m.migrations[version-1]
+	if version == 1 && !dropData {
+		return nil, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, ErrDropsData)
+	}
+	if err := m.apply(migration.down, m.dialect.deleteVersion, migration.Version); err != nil {
+		return nil, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return &migration, nil
+}
+
+func (m *Migrator) apply(statements, record string, args ...any) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(statements); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ensureVersionTable creates schema_migrations for Up. A database that has
+// the ratings table predates migrations and has the initial schema, which is
+// recorded as applied so it is not run again.
+func (m *Migrator) ensureVersionTable() error {
+	exists, err := m.tableExists("schema_migrations")
+	if err != nil || exists {
+		return err
+	}
+
+	legacy, err := m.tableExists("ratings")
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`CREATE TABLE schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL)`)
+	if err != nil {
+		return err
+	}
+
+	if legacy {
+		initial := m.migrations[0]
+		_, err := tx.Exec(m.dialect.recordVersion, initial.Version, initial.Name, time.Now().UTC().Format(TIMESTAMP_FORMAT))
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) tableExists(name string) (bool, error) {
+	var count int
+	err := m.db.QueryRow(m.dialect.tableExists, name).Scan(&count)
+	return count > 0, err
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func newTestMigrator(t *testing.T, path string) *Migrator {
+	t.Helper()
+
+	migrator, err := NewMigrator(path)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	t.Cleanup(func() { migrator.Close() })
+	return migrator
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	migrator := newTestMigrator(t, filepath.Join(t.TempDir(), "database.db"))
+
+	if err := migrator.Check(); !errors.Is(err, ErrSchemaVersion) {
+		t.Fatalf("Expected ErrSchemaVersion for an empty database, got %v", err)
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(applied) != migrator.Latest() || applied[0].Name != "initial" {
+		t.Fatalf("Expected all migrations to be applied, got %v", applied)
+	}
+	if err := migrator.Check(); err != nil {
+		t.Fatalf("Expected the latest version, got %v", err)
+	}
+
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied() {
+			t.Fatalf("Expected migration %d to be applied", status.Version)
+		}
+	}
+
+	if applied, err := migrator.Up(); err != nil || len(applied) != 0 {
+		t.Fatalf("Expected nothing to apply, got %v, %v", applied, err)
+	}
+
+	for version := migrator.Latest(); version > 1; version-- {
+		reverted, err := migrator.Down(false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if reverted.Version != version {
+			t.Fatalf("Expected migration %d to be reverted, got %d", version, reverted.Version)
+		}
+	}
+
+	if _, err := migrator.Down(false); !errors.Is(err, ErrDropsData) {
+		t.Fatalf("Expected ErrDropsData for the initial migration, got %v", err)
+	}
+	if reverted, err := migrator.Down(true); err != nil || reverted.Version != 1 {
+		t.Fatalf("Expected the initial migration to be reverted, got %v, %v", reverted, err)
+	}
+
+	if reverted, err := migrator.Down(false); err != nil || reverted != nil {
+		t.Fatalf("Expected nothing to revert, got %v, %v", reverted, err)
+	}
+	if exists, err := migrator.tableExists("ratings"); err != nil || exists {
+		t.Fatalf("Expected the ratings table to be dropped, got %v, %v", exists, err)
+	}
+
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Expected the migrations to apply again, got %v", err)
+	}
+}
+
+func TestMigrateAdoptsLegacyDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	// The schema of databases created before migrations existed.
+	mustExec(t, db, `CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)`)
+	mustExec(t, db, `CREATE TABLE tickets (id INTEGER PRIMARY KEY AUTOINCREMENT, subject TEXT NOT NULL, created_at DATETIME NOT NULL)`)
+	mustExec(t, db, `CREATE TABLE rating_categories (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, weight REAL NOT NULL)`)
+	mustExec(t, db, `CREATE TABLE ratings (id INTEGER PRIMARY KEY AUTOINCREMENT, rating INTEGER NOT NULL, ticket_id INTEGER NOT NULL, rating_category_id INTEGER NOT NULL, reviewer_id INTEGER NOT NULL, reviewee_id INTEGER NOT NULL, created_at DATETIME NOT NULL)`)
+	mustExec(t, db, `INSERT INTO rating_categories (name, weight) VALUES ('Spelling', 1), ('Grammar', 0.7)`)
+
+	migrator := newTestMigrator(t, path)
+
+	if _, err := migrator.Version(); !errors.Is(err, ErrNoVersionTable) {
+		t.Fatalf("Expected ErrNoVersionTable, got %v", err)
+	}
+	if err := migrator.Check(); !errors.Is(err, ErrSchemaVersion) || !errors.Is(err, ErrNoVersionTable) {
+		t.Fatalf("Expected ErrSchemaVersion and ErrNoVersionTable, got %v", err)
+	}
+	if _, err := migrator.Status(); !errors.Is(err, ErrNoVersionTable) {
+		t.Fatalf("Expected ErrNoVersionTable, got %v", err)
+	}
+	if exists, err := migrator.tableExists("schema_migrations"); err != nil || exists {
+		t.Fatalf("Expected Version, Check and Status not to create schema_migrations, got %v, %v", exists, err)
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(applied) != migrator.Latest()-1 {
+		t.Fatalf("Expected %d migrations to be applied, got %v", migrator.Latest()-1, applied)
+	}
+
+	repo, err := NewRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	weights, err := repo.GetCategoryWeights(2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(weights) != 1 || weights[0].Weight != 0.7 {
+		t.Fatalf("Expected the current weight as the initial one, got %v", weights)
+	}
+
+	for version := migrator.Latest(); version > 1; version-- {
+		if _, err := migrator.Down(false); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if _, err := migrator.Down(false); !errors.Is(err, ErrDropsData) {
+		t.Fatalf("Expected ErrDropsData for the adopted initial schema, got %v", err)
+	}
+	if exists, err := migrator.tableExists("ratings"); err != nil || !exists {
+		t.Fatalf("Expected the ratings table to be kept, got %v, %v", exists, err)
+	}
+}
+
+func TestNewMigratorMemoryStore(t *testing.T) {
+	if _, err := NewMigrator("memory://testdata/fixture.json"); !errors.Is(err, ErrNoMigrations) {
+		t.Fatalf("Expected ErrNoMigrations, got %v", err)
+	}
+}
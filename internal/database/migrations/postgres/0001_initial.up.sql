@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS users (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS tickets (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    subject TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS rating_categories (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    name TEXT NOT NULL,
+    weight DOUBLE PRECISION NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS ratings (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    rating INTEGER NOT NULL,
+    ticket_id BIGINT NOT NULL,
+    rating_category_id BIGINT NOT NULL REFERENCES rating_categories (id),
+    reviewer_id BIGINT NOT NULL,
+    reviewee_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
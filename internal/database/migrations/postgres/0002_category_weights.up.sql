@@ -0,0 +1,15 @@
+CREATE TABLE rating_category_weights (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    rating_category_id BIGINT NOT NULL REFERENCES rating_categories (id),
+    weight DOUBLE PRECISION NOT NULL,
+    effective_from TIMESTAMP NOT NULL
+);
+
+CREATE INDEX rating_category_weights_category_effective_from
+    ON rating_category_weights (rating_category_id, effective_from);
+
+ALTER TABLE rating_categories ADD COLUMN retired_at TIMESTAMP;
+
+-- The current weights become the initial ones, effective for all ratings.
+INSERT INTO rating_category_weights (rating_category_id, weight, effective_from)
+SELECT id, weight, '0001-01-01T00:00:00' FROM rating_categories;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS ratings_created_at ON ratings (created_at);
@@ -0,0 +1,23 @@
+-- The ratings of each category per UTC day and weight, the weight being the
+-- one in effect when the ratings were created.
+CREATE TABLE rating_daily_rollups (
+    day DATE NOT NULL,
+    rating_category_id BIGINT NOT NULL REFERENCES rating_categories (id),
+    weight DOUBLE PRECISION NOT NULL,
+    ratings INTEGER NOT NULL,
+    value_sum BIGINT NOT NULL,
+    PRIMARY KEY (day, rating_category_id, weight)
+);
+
+INSERT INTO rating_daily_rollups (day, rating_category_id, weight, ratings, value_sum)
+SELECT s.day, s.category_id, s.weight, COUNT(*), SUM(s.value)
+FROM (
+    SELECT r.created_at::date AS day, r.rating_category_id AS category_id, COALESCE((
+            SELECT w.weight
+            FROM rating_category_weights w
+            WHERE w.rating_category_id = r.rating_category_id AND w.effective_from <= r.created_at
+            ORDER BY w.effective_from DESC, w.id DESC
+            LIMIT 1), rc.weight) AS weight, r.rating AS value
+    FROM ratings r
+    JOIN rating_categories rc ON rc.id = r.rating_category_id) s
+GROUP BY s.day, s.category_id, s.weight;
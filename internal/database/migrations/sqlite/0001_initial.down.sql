@@ -0,0 +1,4 @@
+DROP TABLE ratings;
+DROP TABLE rating_categories;
+DROP TABLE tickets;
+DROP TABLE users;
@@ -0,0 +1,2 @@
+ALTER TABLE rating_categories DROP COLUMN retired_at;
+DROP TABLE rating_category_weights;
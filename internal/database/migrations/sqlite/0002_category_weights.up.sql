@@ -0,0 +1,16 @@
+CREATE TABLE rating_category_weights (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rating_category_id INTEGER NOT NULL,
+    weight REAL NOT NULL,
+    effective_from DATETIME NOT NULL,
+    FOREIGN KEY (rating_category_id) REFERENCES rating_categories (id)
+);
+
+CREATE INDEX rating_category_weights_category_effective_from
+    ON rating_category_weights (rating_category_id, effective_from);
+
+ALTER TABLE rating_categories ADD COLUMN retired_at DATETIME;
+
+-- The current weights become the initial ones, effective for all ratings.
+INSERT INTO rating_category_weights (rating_category_id, weight, effective_from)
+SELECT id, weight, '0001-01-01T00:00:00' FROM rating_categories;
@@ -0,0 +1 @@
+DROP INDEX ratings_created_at;
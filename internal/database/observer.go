@@ -0,0 +1,32 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// QueryObserver is told how long a repository query took and how many rows
+// it scanned. It is called concurrently.
+type QueryObserver interface {
+	ObserveQuery(query string, duration time.Duration, rows int, err error)
+}
+
+// InstrumentedStore is a store backed by a database/sql connection pool that
+// can report its queries and pool statistics.
+type InstrumentedStore interface {
+	Store
+	// WithQueryObserver returns a store that reports GetOverallScore,
+	// GetDailyScores and GetWeightedRatings to observer. Stores derived from
+	// it with WithCurrentWeights report to the same observer.
+	WithQueryObserver(observer QueryObserver) Store
+	DBStats() sql.DBStats
+}
+
+var _ InstrumentedStore = (*Repository)(nil)
+
+// observeQuery reports a query started at start, observer may be nil.
+func observeQuery(observer QueryObserver, query string, start time.Time, rows int, err error) {
+	if observer != nil {
+		observer.ObserveQuery(query, time.Since(start), rows, err)
+	}
+}
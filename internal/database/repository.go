@@ -1,56 +1,249 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// Repository is the store of a SQLite or a PostgreSQL database. Its queries
+// are written once with ? placeholders and rebound for the dialect.
+// created_at and the other timestamps hold UTC.
 type Repository struct {
-	db *sql.DB
+	db             *sql.DB
+	dialect        dialect
+	currentWeights bool
+	observer       QueryObserver
 }
 
 type Rating struct {
-	Day      string  `json:"day"`
-	Category string  `json:"category"`
-	Value    int32   `json:"value"`
-	Weight   float64 `json:"weight"`
+	ID         int64     `json:"id"`
+	TicketID   int64     `json:"ticket_id"`
+	ReviewerID int64     `json:"reviewer_id"`
+	Reviewer   string    `json:"reviewer"`
+	RevieweeID int64     `json:"reviewee_id"`
+	Reviewee   string    `json:"reviewee"`
+	Day        string    `json:"day"`
+	CategoryID int64     `json:"category_id"`
+	Category   string    `json:"category"`
+	Value      int32     `json:"value"`
+	Weight     float64   `json:"weight"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
+// SQLITE_OPTIONS are added to every SQLite data source. The service and the
+// migrate command may write to the same file, so a locked database is waited
+// for instead of failing with SQLITE_BUSY, and WAL lets readers run while a
+// rating is written.
+const SQLITE_OPTIONS = "_busy_timeout=5000&_journal_mode=WAL"
+
 func NewRepository(dataSourceName string) (*Repository, error) {
-	db, err := sql.Open("sqlite3", dataSourceName)
+	return openRepository(sqliteDialect, sqliteDSN(dataSourceName))
+}
+
+// sqliteDSN adds SQLITE_OPTIONS to the query of dataSourceName. Options
+// given in dataSourceName come first and take precedence.
+func sqliteDSN(dataSourceName string) string {
+	if strings.Contains(dataSourceName, "?") {
+		return dataSourceName + "&" + SQLITE_OPTIONS
+	}
+	return dataSourceName + "?" + SQLITE_OPTIONS
+}
+
+func NewPostgresRepository(dsn string) (*Repository, error) {
+	return openRepository(postgresDialect, dsn)
+}
+
+func openRepository(d dialect, dsn string) (*Repository, error) {
+	db, err := sql.Open(d.driver, dsn)
 	if err != nil {
 		return nil, err
 	}
-	return &Repository{db: db}, nil
+	return &Repository{db: db, dialect: d}, nil
+}
+
+// WithCurrentWeights returns a repository that scores every rating with the
+// current category weight instead of the weight in effect when it was
+// created.
+func (r *Repository) WithCurrentWeights() Store {
+	c := *r
+	c.currentWeights = true
+	return &c
+}
+
+func (r *Repository) WithQueryObserver(observer QueryObserver) Store {
+	c := *r
+	c.observer = observer
+	return &c
+}
+
+func (r *Repository) DBStats() sql.DBStats {
+	return r.db.Stats()
+}
+
+func (r *Repository) weightColumn() string {
+	return weightColumn(r.currentWeights)
+}
+
+// weightColumn selects the weight of the rating category r.rating_category_id
+// for rating r, rc is the joined rating_categories row.
+func weightColumn(currentWeights bool) string {
+	if currentWeights {
+		return "rc.weight"
+	}
+	return `COALESCE((
+			SELECT w.weight
+			FROM rating_category_weights w
+			WHERE w.rating_category_id = r.rating_category_id AND w.effective_from <= r.created_at
+			ORDER BY w.effective_from DESC, w.id DESC
+			LIMIT 1), rc.weight)`
 }
 
 func (r *Repository) Close() error {
 	return r.db.Close()
 }
 
+func (r *Repository) query(query string, args ...any) (*sql.Rows, error) {
+	return r.db.Query(r.dialect.rebind(query), args...)
+}
+
+// GetOverallScore is computed from GetDailyScores, so whole days are read
+// from the rollups.
 func (r *Repository) GetOverallScore(startDate, endDate string) (float32, error) {
-	query := `
-		SELECT COALESCE(100.0 * SUM((r.rating / 5.0) * rc.weight) / SUM(rc.weight), 0) AS overall_score
-        FROM ratings r
-        JOIN rating_categories rc ON rc.id = r.rating_category_id
-        WHERE r.created_at BETWEEN ? AND ?`
-	var overallScore float32
-	err := r.db.QueryRow(query, startDate, endDate).Scan(&overallScore)
+	start := time.Now()
+	scores, err := r.dailyScores(startDate, endDate)
+	observeQuery(r.observer, "GetOverallScore", start, len(scores), err)
 	if err != nil {
 		return 0, err
 	}
-	return overallScore, nil
+	return overallScore(scores), nil
 }
 
 func (r *Repository) GetWeightedRatings(startDate, endDate string) ([]Rating, error) {
-	query := `
-		SELECT DATE(r.created_at) AS day, rc.name as category, r.rating as value, rc.weight as weight
+	start := time.Now()
+	ratings, err := r.queryWeightedRatings(r.weightedRatingsQuery(), startDate, endDate)
+	observeQuery(r.observer, "GetWeightedRatings", start, len(ratings), err)
+	return ratings, err
+}
+
+// StreamWeightedRatings calls fn for the ratings of GetWeightedRatings one
+// row at a time instead of loading the whole range. It stops at the first
+// error returned by fn, and the query is cancelled when ctx is done.
+func (r *Repository) StreamWeightedRatings(ctx context.Context, startDate, endDate string, fn func(Rating) error) error {
+	rows, err := r.db.QueryContext(ctx, r.dialect.rebind(r.weightedRatingsQuery()), startDate, endDate)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rating, err := scanWeightedRating(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(rating); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *Repository) weightedRatingsQuery() string {
+	return fmt.Sprintf(`
+		SELECT %s AS day, r.rating_category_id AS category_id, rc.name as category, r.rating as value, %s as weight, r.created_at
+		FROM ratings r
+		JOIN rating_categories rc ON rc.id = r.rating_category_id
+			WHERE r.created_at BETWEEN ? AND ?
+			ORDER BY r.created_at, r.rating_category_id`, r.dialect.dayOf("r.created_at"), r.weightColumn())
+}
+
+// GetRatedCategoryIDs returns the ids of the categories rated in the range.
+func (r *Repository) GetRatedCategoryIDs(startDate, endDate string) ([]int64, error) {
+	rows, err := r.query(`
+		SELECT DISTINCT rating_category_id
+		FROM ratings
+		WHERE created_at BETWEEN ? AND ?
+		ORDER BY rating_category_id`, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *Repository) GetAgentWeightedRatings(agentID int64, startDate, endDate string) ([]Rating, error) {
+	query := fmt.Sprintf(`
+		SELECT %s AS day, r.rating_category_id AS category_id, rc.name as category, r.rating as value, %s as weight, r.created_at
+		FROM ratings r
+		JOIN rating_categories rc ON rc.id = r.rating_category_id
+			WHERE r.reviewee_id = ? AND r.created_at BETWEEN ? AND ?
+			ORDER BY r.created_at, r.rating_category_id`, r.dialect.dayOf("r.created_at"), r.weightColumn())
+
+	return r.queryWeightedRatings(query, agentID, startDate, endDate)
+}
+
+func (r *Repository) queryWeightedRatings(query string, args ...any) ([]Rating, error) {
+	rows, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []Rating
+	for rows.Next() {
+		rating, err := scanWeightedRating(rows)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
+
+func scanWeightedRating(row scanner) (Rating, error) {
+	var rating Rating
+	var createdAt any
+
+	err := row.Scan(&rating.Day, &rating.CategoryID, &rating.Category, &rating.Value, &rating.Weight, &createdAt)
+	if err != nil {
+		return Rating{}, err
+	}
+
+	rating.CreatedAt, err = parseTimestamp(createdAt)
+	if err != nil {
+		return Rating{}, err
+	}
+
+	return rating, nil
+}
+
+func (r *Repository) GetAgentRatings(startDate, endDate string) ([]Rating, error) {
+	query := fmt.Sprintf(`
+		SELECT r.reviewee_id, COALESCE(u.name, '') AS reviewee, r.rating_category_id AS category_id, rc.name AS category, r.rating AS value, %s AS weight
 		FROM ratings r
 		JOIN rating_categories rc ON rc.id = r.rating_category_id
+		LEFT JOIN users u ON u.id = r.reviewee_id
 			WHERE r.created_at BETWEEN ? AND ?
-			ORDER BY day, r.rating_category_id`
+			ORDER BY r.reviewee_id, r.rating_category_id`, r.weightColumn())
 
-	rows, err := r.db.Query(query, startDate, endDate)
+	rows, err := r.query(query, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
@@ -58,22 +251,159 @@ func (r *Repository) GetWeightedRatings(startDate, endDate string) ([]Rating, er
 
 	var ratings []Rating
 	for rows.Next() {
-		var day, category string
-		var weight float64
-		var value int32
+		var rating Rating
+		err := rows.Scan(&rating.RevieweeID, &rating.Reviewee, &rating.CategoryID, &rating.Category, &rating.Value, &rating.Weight)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
+
+func (r *Repository) GetReviewerRatings(startDate, endDate string) ([]Rating, error) {
+	query := fmt.Sprintf(`
+		SELECT r.reviewer_id, COALESCE(u.name, '') AS reviewer, r.rating_category_id AS category_id, rc.name AS category, r.rating AS value, %s AS weight
+		FROM ratings r
+		JOIN rating_categories rc ON rc.id = r.rating_category_id
+		LEFT JOIN users u ON u.id = r.reviewer_id
+			WHERE r.created_at BETWEEN ? AND ?
+			ORDER BY r.reviewer_id, r.rating_category_id`, r.weightColumn())
+
+	rows, err := r.query(query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		err := rows.Scan(&day, &category, &value, &weight)
+	var ratings []Rating
+	for rows.Next() {
+		var rating Rating
+		err := rows.Scan(&rating.ReviewerID, &rating.Reviewer, &rating.CategoryID, &rating.Category, &rating.Value, &rating.Weight)
 		if err != nil {
 			return nil, err
 		}
+		ratings = append(ratings, rating)
+	}
 
-		ratings = append(ratings, Rating{
-			Day:      day,
-			Category: category,
-			Value:    value,
-			Weight:   weight,
-		})
+	return ratings, rows.Err()
+}
+
+func (r *Repository) GetTicketRatings(startDate, endDate string, afterTicketID int64, limit int) ([]Rating, error) {
+	query := fmt.Sprintf(`
+		SELECT r.ticket_id, %s AS day, r.rating_category_id AS category_id, rc.name AS category, r.rating AS value, %s AS weight
+		FROM ratings r
+		JOIN rating_categories rc ON rc.id = r.rating_category_id
+			WHERE r.created_at BETWEEN ? AND ?
+			AND r.ticket_id IN (
+				SELECT ticket_id
+				FROM ratings
+				WHERE created_at BETWEEN ? AND ? AND ticket_id > ?
+				GROUP BY ticket_id
+				ORDER BY ticket_id
+				LIMIT ?)
+			ORDER BY r.ticket_id, r.rating_category_id`, r.dialect.dayOf("r.created_at"), r.weightColumn())
+
+	rows, err := r.query(query, startDate, endDate, startDate, endDate, afterTicketID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []Rating
+	for rows.Next() {
+		var rating Rating
+		err := rows.Scan(&rating.TicketID, &rating.Day, &rating.CategoryID, &rating.Category, &rating.Value, &rating.Weight)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rating)
 	}
 
 	return ratings, rows.Err()
 }
+
+const insertRatingQuery = `
+	INSERT INTO ratings (rating, ticket_id, rating_category_id, reviewer_id, reviewee_id, created_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	RETURNING id`
+
+func (r *Repository) InsertRating(rating Rating) (int64, error) {
+	ids, err := r.InsertRatings([]Rating{rating})
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// InsertRatings stores all ratings in one transaction and returns their ids
+// in the same order. Nothing is stored when any insert fails. The daily
+// rollups are updated in the same transaction.
+func (r *Repository) InsertRatings(ratings []Rating) ([]int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(r.dialect.rebind(insertRatingQuery))
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	rollupStmt, err := tx.Prepare(r.dialect.rebind(upsertRollupQuery(r.dialect)))
+	if err != nil {
+		return nil, err
+	}
+	defer rollupStmt.Close()
+
+	ids := make([]int64, 0, len(ratings))
+	for _, rating := range ratings {
+		var id int64
+		err := stmt.QueryRow(rating.Value, rating.TicketID, rating.CategoryID,
+			rating.ReviewerID, rating.RevieweeID, rating.CreatedAt.UTC().Format(TIMESTAMP_FORMAT)).Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := rollupStmt.Exec(id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, tx.Commit()
+}
+
+// TIMESTAMP_FORMAT is how created_at is stored, range queries compare
+// against it as text.
+const TIMESTAMP_FORMAT = "2006-01-02T15:04:05"
+
+var timestampFormats = []string{
+	TIMESTAMP_FORMAT,
+	"2006-01-02 15:04:05",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+}
+
+// parseTimestamp accepts created_at as returned by the driver: either a
+// time.Time for DATETIME columns or the stored text. Timestamps without a
+// zone are UTC.
+func parseTimestamp(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case []byte:
+		return parseTimestamp(string(v))
+	case string:
+		for _, format := range timestampFormats {
+			if t, err := time.Parse(format, v); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid timestamp: %q", v)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp type: %T", value)
+	}
+}
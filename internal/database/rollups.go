@@ -0,0 +1,301 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DAY_FORMAT is how rollup days are given and returned.
+const DAY_FORMAT = "2006-01-02"
+
+// DailyScore rolls up the ratings one category received on one UTC day with
+// the same weight: their number and the sum of their values. Scores computed
+// from it are exactly the scores of the ratings themselves, see
+// overallScore.
+type DailyScore struct {
+	Day        string
+	CategoryID int64
+	Weight     float64
+	Ratings    int32
+	ValueSum   int64
+}
+
+// RollupStore is a store that keeps the daily rollups in the database. They
+// are updated with every insert and weight change, RebuildDailyScores is for
+// repairing them after the ratings were changed directly.
+type RollupStore interface {
+	Store
+	// RebuildDailyScores recomputes the rollups of the days in the inclusive
+	// range from the ratings and returns the number of rollup rows written.
+	RebuildDailyScores(startDay, endDay string) (int64, error)
+}
+
+var _ RollupStore = (*Repository)(nil)
+
+// dayRange is a part of a requested range: either whole days, given as
+// days, or a part of a single day, given as timestamps.
+type dayRange struct {
+	start string
+	end   string
+	whole bool
+}
+
+// splitDays splits an inclusive range of timestamps into the whole UTC days
+// it covers and the partial days at its ends, in order.
+func splitDays(startDate, endDate string) ([]dayRange, error) {
+	start, err := time.Parse(TIMESTAMP_FORMAT, startDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.Parse(TIMESTAMP_FORMAT, endDate)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, nil
+	}
+
+	firstDay := start.Truncate(24 * time.Hour)
+	if firstDay.Before(start) {
+		firstDay = firstDay.AddDate(0, 0, 1)
+	}
+	// The first second after the last whole day.
+	afterDays := end.Add(time.Second).Truncate(24 * time.Hour)
+	if !firstDay.Before(afterDays) {
+		return []dayRange{{start: startDate, end: endDate}}, nil
+	}
+
+	var ranges []dayRange
+	if start.Before(firstDay) {
+		ranges = append(ranges, dayRange{start: startDate, end: firstDay.Add(-time.Second).Format(TIMESTAMP_FORMAT)})
+	}
+	ranges = append(ranges, dayRange{start: firstDay.Format(DAY_FORMAT), end: afterDays.AddDate(0, 0, -1).Format(DAY_FORMAT), whole: true})
+	if afterDays.Before(end) || afterDays.Equal(end) {
+		ranges = append(ranges, dayRange{start: afterDays.Format(TIMESTAMP_FORMAT), end: endDate})
+	}
+	return ranges, nil
+}
+
+// dayBounds returns the first and the last second of the inclusive range of
+// days as timestamps.
+func dayBounds(startDay, endDay string) (string, string, error) {
+	start, err := time.Parse(DAY_FORMAT, startDay)
+	if err != nil {
+		return "", "", err
+	}
+	end, err := time.Parse(DAY_FORMAT, endDay)
+	if err != nil {
+		return "", "", err
+	}
+	if end.Before(start) {
+		return "", "", fmt.Errorf("end day %s is before start day %s", endDay, startDay)
+	}
+	return start.Format(TIMESTAMP_FORMAT), end.AddDate(0, 0, 1).Add(-time.Second).Format(TIMESTAMP_FORMAT), nil
+}
+
+// aggregateDailyScores rolls up ratings the way the rollup tables do.
+func aggregateDailyScores(ratings []Rating) []DailyScore {
+	type key struct {
+		day        string
+		categoryID int64
+		weight     float64
+	}
+
+	byKey := make(map[key]*DailyScore)
+	var scores []*DailyScore
+	for _, rating := range ratings {
+		k := key{rating.CreatedAt.UTC().Format(DAY_FORMAT), rating.CategoryID, rating.Weight}
+		score, ok := byKey[k]
+		if !ok {
+			score = &DailyScore{Day: k.day, CategoryID: k.categoryID, Weight: k.weight}
+			byKey[k] = score
+			scores = append(scores, score)
+		}
+		score.Ratings++
+		score.ValueSum += int64(rating.Value)
+	}
+
+	result := make([]DailyScore, 0, len(scores))
+	for _, score := range scores {
+		result = append(result, *score)
+	}
+	sortDailyScores(result)
+	return result
+}
+
+func sortDailyScores(scores []DailyScore) {
+	sort.Slice(scores, func(i, j int) bool {
+		a, b := scores[i], scores[j]
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.CategoryID != b.CategoryID {
+			return a.CategoryID < b.CategoryID
+		}
+		return a.Weight < b.Weight
+	})
+}
+
+// overallScore is the weighted average of all rolled up ratings as a
+// percentage. Values are summed per weight as integers first, so the result
+// does not depend on how the ratings were grouped into days.
+func overallScore(scores []DailyScore) float32 {
+	type sums struct {
+		ratings int64
+		values  int64
+	}
+
+	byWeight := make(map[float64]*sums)
+	var weights []float64
+	for _, score := range scores {
+		s, ok := byWeight[score.Weight]
+		if !ok {
+			s = &sums{}
+			byWeight[score.Weight] = s
+			weights = append(weights, score.Weight)
+		}
+		s.ratings += int64(score.Ratings)
+		s.values += score.ValueSum
+	}
+	sort.Float64s(weights)
+
+	var weightSum, valueSum float64
+	for _, weight := range weights {
+		weightSum += weight * float64(byWeight[weight].ratings)
+		valueSum += weight * float64(byWeight[weight].values) / 5.0
+	}
+	if weightSum == 0 {
+		return 0
+	}
+	return float32(100.0 * valueSum / weightSum)
+}
+
+func (r *Repository) queryDailyScores(query string, args ...any) ([]DailyScore, error) {
+	rows, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []DailyScore
+	for rows.Next() {
+		var score DailyScore
+		if err := rows.Scan(&score.Day, &score.CategoryID, &score.Weight, &score.Ratings, &score.ValueSum); err != nil {
+			return nil, err
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
+
+// ratingScoresQuery rolls up the ratings matching condition with the weight
+// given by weight, for rating r and its category rc.
+func ratingScoresQuery(day, weight, condition string) string {
+	return fmt.Sprintf(`
+		SELECT s.day, s.category_id, s.weight, COUNT(*), SUM(s.value)
+		FROM (
+			SELECT %s AS day, r.rating_category_id AS category_id, %s AS weight, r.rating AS value
+			FROM ratings r
+			JOIN rating_categories rc ON rc.id = r.rating_category_id
+			WHERE %s) s
+		GROUP BY s.day, s.category_id, s.weight
+		ORDER BY s.day, s.category_id, s.weight`, day, weight, condition)
+}
+
+// upsertRollupQuery adds the rating with the given id to its rollup.
+func upsertRollupQuery(d dialect) string {
+	return fmt.Sprintf(`
+	INSERT INTO rating_daily_rollups AS d (day, rating_category_id, weight, ratings, value_sum)
+	SELECT %s, r.rating_category_id, %s, 1, r.rating
+	FROM ratings r
+	JOIN rating_categories rc ON rc.id = r.rating_category_id
+	WHERE r.id = ?
+	ON CONFLICT (day, rating_category_id, weight) DO UPDATE
+	SET ratings = d.ratings + excluded.ratings, value_sum = d.value_sum + excluded.value_sum`, d.dateOf("r.created_at"), weightColumn(false))
+}
+
+func (r *Repository) GetDailyScores(startDate, endDate string) ([]DailyScore, error) {
+	start := time.Now()
+	scores, err := r.dailyScores(startDate, endDate)
+	observeQuery(r.observer, "GetDailyScores", start, len(scores), err)
+	return scores, err
+}
+
+func (r *Repository) dailyScores(startDate, endDate string) ([]DailyScore, error) {
+	ranges, err := splitDays(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var scores []DailyScore
+	for _, days := range ranges {
+		query := ratingScoresQuery(r.dialect.dayOf("r.created_at"), r.weightColumn(), "r.created_at BETWEEN ? AND ?")
+		if days.whole {
+			query = r.rollupsQuery()
+		}
+		rangeScores, err := r.queryDailyScores(query, days.start, days.end)
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, rangeScores...)
+	}
+	return scores, nil
+}
+
+func (r *Repository) rollupsQuery() string {
+	weight := "d.weight"
+	if r.currentWeights {
+		weight = "rc.weight"
+	}
+	return fmt.Sprintf(`
+		SELECT %s, d.rating_category_id, %s AS weight, CAST(SUM(d.ratings) AS BIGINT), CAST(SUM(d.value_sum) AS BIGINT)
+		FROM rating_daily_rollups d
+		JOIN rating_categories rc ON rc.id = d.rating_category_id
+		WHERE d.day BETWEEN ? AND ?
+		GROUP BY d.day, d.rating_category_id, 3
+		ORDER BY d.day, d.rating_category_id, 3`, r.dialect.dayOf("d.day"), weight)
+}
+
+func (r *Repository) RebuildDailyScores(startDay, endDay string) (int64, error) {
+	start, end, err := dayBounds(startDay, endDay)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(r.dialect.rebind(`DELETE FROM rating_daily_rollups WHERE day BETWEEN ? AND ?`), startDay, endDay); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(r.dialect.rebind(`INSERT INTO rating_daily_rollups (day, rating_category_id, weight, ratings, value_sum)`+
+		ratingScoresQuery(r.dialect.dateOf("r.created_at"), weightColumn(false), "r.created_at BETWEEN ? AND ?")), start, end)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return rows, tx.Commit()
+}
+
+// rebuildCategoryRollups recomputes the rollups of a category from the day of
+// from on, after a weight effective from then was recorded.
+func rebuildCategoryRollups(tx *sql.Tx, d dialect, categoryID int64, from time.Time) error {
+	day := from.UTC().Format(DAY_FORMAT)
+	if _, err := tx.Exec(d.rebind(`DELETE FROM rating_daily_rollups WHERE rating_category_id = ? AND day >= ?`), categoryID, day); err != nil {
+		return err
+	}
+	_, err := tx.Exec(d.rebind(`INSERT INTO rating_daily_rollups (day, rating_category_id, weight, ratings, value_sum)`+
+		ratingScoresQuery(d.dateOf("r.created_at"), weightColumn(false), "r.rating_category_id = ? AND r.created_at >= ?")),
+		categoryID, day+"T00:00:00")
+	return err
+}
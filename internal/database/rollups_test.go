@@ -0,0 +1,211 @@
+package database
+
+import (
+	"math/rand"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitDays(t *testing.T) {
+	tests := []struct {
+		start    string
+		end      string
+		expected []dayRange
+	}{
+		{"2025-01-01T00:00:00", "2025-01-03T23:59:59", []dayRange{
+			{"2025-01-01", "2025-01-03", true},
+		}},
+		{"2025-01-01T09:00:00", "2025-01-03T23:59:59", []dayRange{
+			{"2025-01-01T09:00:00", "2025-01-01T23:59:59", false},
+			{"2025-01-02", "2025-01-03", true},
+		}},
+		{"2025-01-01T00:00:00", "2025-01-03T12:00:00", []dayRange{
+			{"2025-01-01", "2025-01-02", true},
+			{"2025-01-03T00:00:00", "2025-01-03T12:00:00", false},
+		}},
+		{"2025-01-01T09:00:00", "2025-01-03T00:00:00", []dayRange{
+			{"2025-01-01T09:00:00", "2025-01-01T23:59:59", false},
+			{"2025-01-02", "2025-01-02", true},
+			{"2025-01-03T00:00:00", "2025-01-03T00:00:00", false},
+		}},
+		{"2025-01-01T09:00:00", "2025-01-02T08:00:00", []dayRange{
+			{"2025-01-01T09:00:00", "2025-01-02T08:00:00", false},
+		}},
+		{"2025-01-01T09:00:00", "2025-01-01T10:00:00", []dayRange{
+			{"2025-01-01T09:00:00", "2025-01-01T10:00:00", false},
+		}},
+		{"2025-01-02T00:00:00", "2025-01-01T23:59:59", nil},
+	}
+	for _, test := range tests {
+		ranges, err := splitDays(test.start, test.end)
+		if err != nil {
+			t.Fatalf("Expected no error for %s to %s, got %v", test.start, test.end, err)
+		}
+		if !reflect.DeepEqual(ranges, test.expected) {
+			t.Fatalf("Expected %v for %s to %s, got %v", test.expected, test.start, test.end, ranges)
+		}
+	}
+
+	if _, err := splitDays("2025-01-01", FIXTURE_END); err == nil {
+		t.Fatalf("Expected an error for a day instead of a timestamp")
+	}
+}
+
+func TestSQLiteRollups(t *testing.T) {
+	testRollups(t, func(t *testing.T, fixture Fixture) RollupStore {
+		return openSQLite(t, fixture).(*Repository)
+	})
+}
+
+func TestPostgresRollups(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	testRollups(t, func(t *testing.T, fixture Fixture) RollupStore {
+		return openPostgres(t, dsn, fixture).(*Repository)
+	})
+}
+
+// testRollups checks that the rollups kept by a store score exactly like the
+// ratings they roll up.
+func testRollups(t *testing.T, open func(*testing.T, Fixture) RollupStore) {
+	fixture := loadFixture(t)
+
+	t.Run("Fixture", func(t *testing.T) {
+		store := open(t, fixture)
+		expectParity(t, store)
+	})
+
+	t.Run("Ingest", func(t *testing.T) {
+		store := open(t, fixture)
+
+		random := rand.New(rand.NewSource(1))
+		ratings := make([]Rating, 0, 500)
+		for range cap(ratings) {
+			ratings = append(ratings, Rating{
+				Value:      int32(random.Intn(6)),
+				TicketID:   int64(random.Intn(50) + 1),
+				CategoryID: int64(random.Intn(3) + 1),
+				ReviewerID: 1,
+				RevieweeID: 3,
+				CreatedAt:  date(1, 0, 0).Add(time.Duration(random.Intn(10*24*3600)) * time.Second),
+			})
+		}
+		for _, rating := range ratings[:10] {
+			if _, err := store.InsertRating(rating); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		if _, err := store.InsertRatings(ratings[10:]); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expectParity(t, store)
+
+		// Reweights effective in the past change the weight of ratings that
+		// are already rolled up.
+		if _, err := store.ReweightCategory(1, 0.3, date(4, 12, 0)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := store.ReweightCategory(2, 2.5, date(8, 0, 0)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expectParity(t, store)
+	})
+
+	t.Run("Rebuild", func(t *testing.T) {
+		store := open(t, fixture)
+
+		before, err := store.GetDailyScores(FIXTURE_START, FIXTURE_END)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		rows, err := store.RebuildDailyScores("2025-01-01", "2025-01-03")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rows != int64(len(before)) {
+			t.Fatalf("Expected %d rollups, got %d", len(before), rows)
+		}
+		after, err := store.GetDailyScores(FIXTURE_START, FIXTURE_END)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(before, after) {
+			t.Fatalf("Expected %v after the rebuild, got %v", before, after)
+		}
+
+		if _, err := store.RebuildDailyScores("2025-01-03", "2025-01-01"); err == nil {
+			t.Fatalf("Expected an error for an end day before the start day")
+		}
+	})
+}
+
+// expectParity compares the rollups and the overall score of the store with
+// the ones computed from its weighted ratings, for whole and partial days.
+func expectParity(t *testing.T, store Store) {
+	t.Helper()
+
+	ranges := [][2]string{
+		{FIXTURE_START, FIXTURE_END},
+		{"2025-01-01T09:00:00", "2025-01-02T10:00:00"},
+		{"2025-01-01T12:00:00", "2025-01-10T23:59:59"},
+		{"2025-01-02T00:00:00", "2025-01-08T06:00:00"},
+		{"2024-12-01T00:00:00", "2025-02-01T00:00:00"},
+		{"2025-01-05T10:00:00", "2025-01-05T11:00:00"},
+	}
+	for _, weights := range []Store{store, store.WithCurrentWeights()} {
+		for _, r := range ranges {
+			ratings, err := weights.GetWeightedRatings(r[0], r[1])
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			scores, err := weights.GetDailyScores(r[0], r[1])
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			expected := aggregateDailyScores(ratings)
+			if len(expected) != 0 || len(scores) != 0 {
+				if !reflect.DeepEqual(scores, expected) {
+					t.Fatalf("Expected %v for %s to %s, got %v", expected, r[0], r[1], scores)
+				}
+			}
+
+			overall, err := weights.GetOverallScore(r[0], r[1])
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if expectedOverall := overallScore(expected); overall != expectedOverall {
+				t.Fatalf("Expected overall score %v for %s to %s, got %v", expectedOverall, r[0], r[1], overall)
+			}
+		}
+	}
+}
+
+func TestMemoryStoreDailyScores(t *testing.T) {
+	store, err := NewMemoryStore(loadFixture(t))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	scores, err := store.GetDailyScores(FIXTURE_START, FIXTURE_END)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []DailyScore{
+		{Day: "2025-01-01", CategoryID: 1, Weight: 1, Ratings: 2, ValueSum: 7},
+		{Day: "2025-01-01", CategoryID: 2, Weight: 0.7, Ratings: 1, ValueSum: 3},
+		{Day: "2025-01-01", CategoryID: 3, Weight: 1, Ratings: 1, ValueSum: 4},
+		{Day: "2025-01-02", CategoryID: 1, Weight: 1, Ratings: 1, ValueSum: 4},
+		{Day: "2025-01-02", CategoryID: 3, Weight: 1.2, Ratings: 1, ValueSum: 5},
+		{Day: "2025-01-02", CategoryID: 4, Weight: 0.5, Ratings: 1, ValueSum: 1},
+		{Day: "2025-01-03", CategoryID: 2, Weight: 0.7, Ratings: 1, ValueSum: 0},
+	}
+	if !reflect.DeepEqual(scores, expected) {
+		t.Fatalf("Expected %v, got %v", expected, scores)
+	}
+	expectParity(t, store)
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Store is the data access the ratings service needs. Ranges are inclusive
+// and given as UTC timestamps in TIMESTAMP_FORMAT. Methods returning ratings
+// only fill the fields their query selects, in the documented order.
+type Store interface {
+	// WithCurrentWeights returns a store that scores every rating with the
+	// current category weight instead of the weight in effect when it was
+	// created.
+	WithCurrentWeights() Store
+	Close() error
+
+	GetOverallScore(startDate, endDate string) (float32, error)
+	// GetDailyScores rolls up the ratings per UTC day, category and weight,
+	// ordered by day, category id and weight. Whole days of the range are
+	// read from the rollup table where the store keeps one.
+	GetDailyScores(startDate, endDate string) ([]DailyScore, error)
+	// GetWeightedRatings and StreamWeightedRatings are ordered by creation
+	// time and category id.
+	GetWeightedRatings(startDate, endDate string) ([]Rating, error)
+	StreamWeightedRatings(ctx context.Context, startDate, endDate string, fn func(Rating) error) error
+	GetAgentWeightedRatings(agentID int64, startDate, endDate string) ([]Rating, error)
+	GetRatedCategoryIDs(startDate, endDate string) ([]int64, error)
+	// GetAgentRatings is ordered by reviewee id, GetReviewerRatings by
+	// reviewer id and GetTicketRatings by ticket id, then category id.
+	GetAgentRatings(startDate, endDate string) ([]Rating, error)
+	GetReviewerRatings(startDate, endDate string) ([]Rating, error)
+	GetTicketRatings(startDate, endDate string, afterTicketID int64, limit int) ([]Rating, error)
+
+	InsertRating(rating Rating) (int64, error)
+	InsertRatings(ratings []Rating) ([]int64, error)
+
+	GetCategories() ([]Category, error)
+	GetCategory(id int64) (Category, error)
+	GetCategoryWeights(id int64) ([]CategoryWeight, error)
+	CreateCategory(name string, weight float64) (Category, error)
+	RenameCategory(id int64, name string) (Category, error)
+	ReweightCategory(id int64, weight float64, effectiveFrom time.Time) (Category, error)
+	RetireCategory(id int64, retiredAt time.Time) (Category, error)
+}
+
+var (
+	_ Store = (*Repository)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
+
+// Open picks the store by the scheme of dsn: postgres:// and postgresql://
+// for PostgreSQL, memory:// for a JSON fixture loaded into memory and
+// sqlite:// or a plain file path for SQLite. A plain path ending in .json is
+// loaded into memory as well. Open does not change the schema, see Migrator.
+func Open(dsn string) (Store, error) {
+	scheme, source := parseDSN(dsn)
+	switch scheme {
+	case "postgres":
+		return NewPostgresRepository(source)
+	case "memory":
+		return LoadMemoryStore(source)
+	case "sqlite":
+		return NewRepository(source)
+	default:
+		return nil, fmt.Errorf("unsupported database scheme %q", scheme)
+	}
+}
+
+// parseDSN returns the kind of store dsn selects and what to open it with.
+func parseDSN(dsn string) (scheme, source string) {
+	scheme, rest, found := strings.Cut(dsn, "://")
+	switch {
+	case !found && strings.HasSuffix(dsn, ".json"):
+		return "memory", dsn
+	case !found:
+		return "sqlite", dsn
+	case scheme == "postgres" || scheme == "postgresql":
+		return "postgres", dsn
+	default:
+		return scheme, rest
+	}
+}
+
+// RedactDSN hides the password of a URL style dsn, so it can be logged.
+func RedactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil {
+		return dsn
+	}
+	return u.Redacted()
+}
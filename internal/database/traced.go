@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"helpdesk-ratings/internal/tracing"
+)
+
+const TRACER_NAME = "helpdesk-ratings/internal/database"
+
+// TracedStore records a span for every query of another store, as a child of
+// the span in the context the store was created for. The store methods take
+// no context, so a TracedStore is created per request and must not outlive
+// it.
+type TracedStore struct {
+	ctx    context.Context
+	store  Store
+	tracer trace.Tracer
+}
+
+var _ Store = (*TracedStore)(nil)
+
+// NewTracedStore traces the queries of store made on behalf of ctx, with the
+// global tracer provider.
+func NewTracedStore(ctx context.Context, store Store) *TracedStore {
+	return &TracedStore{ctx: ctx, store: store, tracer: otel.Tracer(TRACER_NAME)}
+}
+
+func (t *TracedStore) WithCurrentWeights() Store {
+	return &TracedStore{ctx: t.ctx, store: t.store.WithCurrentWeights(), tracer: t.tracer}
+}
+
+func (t *TracedStore) Close() error {
+	return t.store.Close()
+}
+
+func (t *TracedStore) GetOverallScore(startDate, endDate string) (float32, error) {
+	return traced(t, "GetOverallScore", rangeAttributes(startDate, endDate), nil, func() (float32, error) {
+		return t.store.GetOverallScore(startDate, endDate)
+	})
+}
+
+func (t *TracedStore) GetDailyScores(startDate, endDate string) ([]DailyScore, error) {
+	return traced(t, "GetDailyScores", rangeAttributes(startDate, endDate), countRows[DailyScore], func() ([]DailyScore, error) {
+		return t.store.GetDailyScores(startDate, endDate)
+	})
+}
+
+func (t *TracedStore) GetWeightedRatings(startDate, endDate string) ([]Rating, error) {
+	return traced(t, "GetWeightedRatings", rangeAttributes(startDate, endDate), countRows[Rating], func() ([]Rating, error) {
+		return t.store.GetWeightedRatings(startDate, endDate)
+	})
+}
+
+// StreamWeightedRatings traces the whole stream, including the time spent in
+// fn.
+func (t *TracedStore) StreamWeightedRatings(ctx context.Context, startDate, endDate string, fn func(Rating) error) error {
+	ctx, span := t.tracer.Start(ctx, "StreamWeightedRatings", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(rangeAttributes(startDate, endDate)...))
+
+	rows := 0
+	err := t.store.StreamWeightedRatings(ctx, startDate, endDate, func(rating Rating) error {
+		rows++
+		return fn(rating)
+	})
+	span.SetAttributes(attribute.Int("db.rows", rows))
+	tracing.End(span, err)
+	return err
+}
+
+func (t *TracedStore) GetAgentWeightedRatings(agentID int64, startDate, endDate string) ([]Rating, error) {
+	attributes := append(rangeAttributes(startDate, endDate), attribute.Int64("ratings.agent_id", agentID))
+	return traced(t, "GetAgentWeightedRatings", attributes, countRows[Rating], func() ([]Rating, error) {
+		return t.store.GetAgentWeightedRatings(agentID, startDate, endDate)
+	})
+}
+
+func (t *TracedStore) GetRatedCategoryIDs(startDate, endDate string) ([]int64, error) {
+	return traced(t, "GetRatedCategoryIDs", rangeAttributes(startDate, endDate), countRows[int64], func() ([]int64, error) {
+		return t.store.GetRatedCategoryIDs(startDate, endDate)
+	})
+}
+
+func (t *TracedStore) GetAgentRatings(startDate, endDate string) ([]Rating, error) {
+	return traced(t, "GetAgentRatings", rangeAttributes(startDate, endDate), countRows[Rating], func() ([]Rating, error) {
+		return t.store.GetAgentRatings(startDate, endDate)
+	})
+}
+
+func (t *TracedStore) GetReviewerRatings(startDate, endDate string) ([]Rating, error) {
+	return traced(t, "GetReviewerRatings", rangeAttributes(startDate, endDate), countRows[Rating], func() ([]Rating, error) {
+		return t.store.GetReviewerRatings(startDate, endDate)
+	})
+}
+
+func (t *TracedStore) GetTicketRatings(startDate, endDate string, afterTicketID int64, limit int) ([]Rating, error) {
+	attributes := append(rangeAttributes(startDate, endDate),
+		attribute.Int64("ratings.after_ticket_id", afterTicketID), attribute.Int("ratings.limit", limit))
+	return traced(t, "GetTicketRatings", attributes, countRows[Rating], func() ([]Rating, error) {
+		return t.store.GetTicketRatings(startDate, endDate, afterTicketID, limit)
+	})
+}
+
+func (t *TracedStore) InsertRating(rating Rating) (int64, error) {
+	return traced(t, "InsertRating", nil, nil, func() (int64, error) {
+		return t.store.InsertRating(rating)
+	})
+}
+
+func (t *TracedStore) InsertRatings(ratings []Rating) ([]int64, error) {
+	return traced(t, "InsertRatings", []attribute.KeyValue{attribute.Int("ratings.count", len(ratings))}, countRows[int64], func() ([]int64, error) {
+		return t.store.InsertRatings(ratings)
+	})
+}
+
+func (t *TracedStore) GetCategories() ([]Category, error) {
+	return traced(t, "GetCategories", nil, countRows[Category], t.store.GetCategories)
+}
+
+func (t *TracedStore) GetCategory(id int64) (Category, error) {
+	return traced(t, "GetCategory", categoryAttributes(id), nil, func() (Category, error) {
+		return t.store.GetCategory(id)
+	})
+}
+
+func (t *TracedStore) GetCategoryWeights(id int64) ([]CategoryWeight, error) {
+	return traced(t, "GetCategoryWeights", categoryAttributes(id), countRows[CategoryWeight], func() ([]CategoryWeight, error) {
+		return t.store.GetCategoryWeights(id)
+	})
+}
+
+func (t *TracedStore) CreateCategory(name string, weight float64) (Category, error) {
+	return traced(t, "CreateCategory", nil, nil, func() (Category, error) {
+		return t.store.CreateCategory(name, weight)
+	})
+}
+
+func (t *TracedStore) RenameCategory(id int64, name string) (Category, error) {
+	return traced(t, "RenameCategory", categoryAttributes(id), nil, func() (Category, error) {
+		return t.store.RenameCategory(id, name)
+	})
+}
+
+func (t *TracedStore) ReweightCategory(id int64, weight float64, effectiveFrom time.Time) (Category, error) {
+	return traced(t, "ReweightCategory", categoryAttributes(id), nil, func() (Category, error) {
+		return t.store.ReweightCategory(id, weight, effectiveFrom)
+	})
+}
+
+func (t *TracedStore) RetireCategory(id int64, retiredAt time.Time) (Category, error) {
+	return traced(t, "RetireCategory", categoryAttributes(id), nil, func() (Category, error) {
+		return t.store.RetireCategory(id, retiredAt)
+	})
+}
+
+// traced runs query in a span named after the store method. When rows is
+// set, the number of rows of the result is recorded as db.rows.
+func traced[T any](t *TracedStore, name string, attributes []attribute.KeyValue, rows func(T) int, query func() (T, error)) (T, error) {
+	_, span := t.tracer.Start(t.ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+
+	result, err := query()
+	if err == nil && rows != nil {
+		span.SetAttributes(attribute.Int("db.rows", rows(result)))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+func countRows[T any](rows []T) int {
+	return len(rows)
+}
+
+func rangeAttributes(startDate, endDate string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("ratings.start_date", startDate),
+		attribute.String("ratings.end_date", endDate),
+	}
+}
+
+func categoryAttributes(id int64) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.Int64("ratings.category_id", id)}
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedStore(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	memory, err := NewMemoryStore(loadFixture(t))
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	store := NewTracedStore(ctx, memory)
+	if _, err := store.GetWeightedRatings(FIXTURE_START, FIXTURE_END); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.WithCurrentWeights().GetDailyScores(FIXTURE_START, "2025-01-01T23:59:59"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.GetCategory(99); err == nil {
+		t.Fatalf("Expected an error for a missing category")
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("Expected 4 spans, got %d", len(spans))
+	}
+	tests := []struct {
+		name string
+		rows int64
+	}{
+		{"GetWeightedRatings", 8},
+		{"GetDailyScores", 3},
+		{"GetCategory", -1},
+	}
+	for i, test := range tests {
+		span := spans[i]
+		if span.Name() != test.name || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("Expected %s as a child of the request, got %s", test.name, span.Name())
+		}
+		rows, ok := attributeValue(span.Attributes(), "db.rows")
+		if test.rows < 0 {
+			if ok || span.Status().Code != codes.Error {
+				t.Fatalf("Expected %s to fail without rows, got %v", test.name, span.Attributes())
+			}
+			continue
+		}
+		if !ok || rows.AsInt64() != test.rows {
+			t.Fatalf("Expected %d rows for %s, got %v", test.rows, test.name, span.Attributes())
+		}
+		if start, _ := attributeValue(span.Attributes(), "ratings.start_date"); start.AsString() != FIXTURE_START {
+			t.Fatalf("Expected the range on %s, got %v", test.name, span.Attributes())
+		}
+	}
+}
+
+func attributeValue(attributes []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
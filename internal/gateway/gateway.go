@@ -0,0 +1,294 @@
+// Package gateway serves the unary RPCs of ratings.Service as JSON over HTTP
+// for clients that cannot speak gRPC. Requests are forwarded to the gRPC
+// server through a client connection, so they go through the same server
+// options as native gRPC calls.
+package gateway
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"helpdesk-ratings/internal/certs"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// METADATA_HEADER_PREFIX marks HTTP headers that are forwarded to the gRPC
+// server as metadata, with the prefix removed. Authorization is always
+// forwarded.
+const METADATA_HEADER_PREFIX = "Grpc-Metadata-"
+
+// REQUEST_ID_HEADER is forwarded as metadata, and the request ID the server
+// answers with is returned in the same header.
+const REQUEST_ID_HEADER = "x-request-id"
+
+// MAX_BODY_SIZE bounds the JSON body of a request, larger ones are answered
+// with 413 Request Entity Too Large.
+const MAX_BODY_SIZE = 4 << 20
+
+//go:embed openapi.json
+var openAPI []byte
+
+var (
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	unmarshalOptions = protojson.UnmarshalOptions{}
+	pathParam        = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}
+
+func routes(client pb.ServiceClient) []route {
+	return []route{
+		newRoute(http.MethodGet, "/v1/scores/aggregated", client.GetAggregatedScores),
+		newDownload(http.MethodGet, "/v1/scores/aggregated.csv", client.ExportAggregatedScores, csvDownload),
+		newRoute(http.MethodGet, "/v1/scores/overall", client.GetOverallScore),
+		newRoute(http.MethodGet, "/v1/scores/compare", client.CompareScores),
+		newRoute(http.MethodGet, "/v1/tickets/scores", client.GetTicketScores),
+		newRoute(http.MethodGet, "/v1/agents/scores", client.GetAgentScores),
+		newRoute(http.MethodGet, "/v1/agents/{agent_id}/scores/aggregated", client.GetAgentAggregatedScores),
+		newRoute(http.MethodGet, "/v1/reviewers/calibration", client.GetReviewerCalibration),
+		newRoute(http.MethodPost, "/v1/ratings", client.SubmitRating),
+		newRoute(http.MethodGet, "/v1/categories", client.ListCategories),
+		newRoute(http.MethodPost, "/v1/categories", client.CreateCategory),
+		newRoute(http.MethodPatch, "/v1/categories/{id}", client.RenameCategory),
+		newRoute(http.MethodPost, "/v1/categories/{id}/weights", client.ReweightCategory),
+		newRoute(http.MethodPost, "/v1/categories/{id}/retire", client.RetireCategory),
+	}
+}
+
+// NewHandler returns the HTTP API, plus its OpenAPI description at
+// /openapi.json.
+func NewHandler(client pb.ServiceClient) http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range routes(client) {
+		mux.HandleFunc(route.method+" "+route.path, route.handler)
+	}
+	mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+	return mux
+}
+
+// newRoute maps an HTTP request to the request message of call: GET requests
+// are read from the query string, other methods from a JSON body. Path
+// parameters are request fields with the same name and override both.
+func newRoute[T any, Req interface {
+	*T
+	proto.Message
+}, Resp proto.Message](method, path string, call func(context.Context, Req, ...grpc.CallOption) (Resp, error)) route {
+	return newDownload(method, path, call, func(w http.ResponseWriter, resp Resp) {
+		writeMessage(w, http.StatusOK, resp)
+	})
+}
+
+// newDownload is newRoute with a custom response writer, for responses that
+// are not sent as JSON.
+func newDownload[T any, Req interface {
+	*T
+	proto.Message
+}, Resp proto.Message](method, path string, call func(context.Context, Req, ...grpc.CallOption) (Resp, error), write func(http.ResponseWriter, Resp)) route {
+	var params []string
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		params = append(params, match[1])
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		req := Req(new(T))
+
+		fields := make(map[string][]string)
+		if method == http.MethodGet {
+			fields = r.URL.Query()
+		} else if err := decodeBody(w, r, req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				s := status.Newf(codes.InvalidArgument, "request body is larger than %d bytes", tooLarge.Limit)
+				writeMessage(w, http.StatusRequestEntityTooLarge, s.Proto())
+				return
+			}
+			writeError(w, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err))
+			return
+		}
+		for _, param := range params {
+			fields[param] = []string{r.PathValue(param)}
+		}
+		if err := setFields(req, fields); err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+
+		var header metadata.MD
+		resp, err := call(outgoingContext(r), req, grpc.Header(&header))
+		if requestID := header.Get(REQUEST_ID_HEADER); len(requestID) > 0 {
+			w.Header().Set(REQUEST_ID_HEADER, requestID[0])
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		write(w, resp)
+	}
+	return route{method: method, path: path, handler: handler}
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, req proto.Message) error {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MAX_BODY_SIZE))
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return unmarshalOptions.Unmarshal(body, req)
+}
+
+// setFields sets the fields of req from string values, keyed by the proto
+// or JSON field name. Each value is converted to its JSON form and read with
+// protojson, so query parameters accept the same formats as a JSON body,
+// e.g. RFC 3339 timestamps and enum names or numbers.
+func setFields(req proto.Message, values map[string][]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fields := req.ProtoReflect().Descriptor().Fields()
+	object := make(map[string]json.RawMessage, len(values))
+	for name, vs := range values {
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil {
+			field = fields.ByJSONName(name)
+		}
+		if field == nil {
+			return fmt.Errorf("unknown field %q", name)
+		}
+		if field.IsList() || field.IsMap() || (field.Kind() == protoreflect.MessageKind && field.Message().FullName() != "google.protobuf.Timestamp") {
+			return fmt.Errorf("field %q cannot be set from a parameter", name)
+		}
+		if len(vs) != 1 {
+			return fmt.Errorf("field %q is given %d times", name, len(vs))
+		}
+		object[string(field.Name())] = jsonValue(field, vs[0])
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	params := req.ProtoReflect().New().Interface()
+	if err := unmarshalOptions.Unmarshal(data, params); err != nil {
+		return err
+	}
+	proto.Merge(req, params)
+	return nil
+}
+
+func jsonValue(field protoreflect.FieldDescriptor, value string) json.RawMessage {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		if value == "" {
+			value = "true"
+		}
+		return json.RawMessage(value)
+	case protoreflect.EnumKind:
+		if _, err := strconv.ParseInt(value, 10, 32); err == nil {
+			return json.RawMessage(value)
+		}
+	}
+	quoted, _ := json.Marshal(value)
+	return quoted
+}
+
+// outgoingContext forwards the Authorization and X-Request-Id headers, the
+// headers with METADATA_HEADER_PREFIX, the verified mTLS client certificate
+// and the trace context.
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for name, values := range r.Header {
+		if name == "Authorization" {
+			md.Append("authorization", values...)
+		} else if name == http.CanonicalHeaderKey(REQUEST_ID_HEADER) {
+			md.Append(REQUEST_ID_HEADER, values...)
+		} else if key, ok := strings.CutPrefix(name, METADATA_HEADER_PREFIX); ok {
+			md.Append(key, values...)
+		}
+	}
+	certs.ForwardClientCertificate(md, r.TLS)
+	// The trace context is continued by the gRPC client, when it is
+	// instrumented.
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func csvDownload(w http.ResponseWriter, resp *pb.ExportAggregatedScoresResponse) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": resp.Filename}))
+	w.Write(resp.Csv)
+}
+
+func writeMessage(w http.ResponseWriter, code int, message proto.Message) {
+	data, err := marshalOptions.Marshal(message)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(data)
+}
+
+// writeError writes the gRPC status of err as a google.rpc.Status object.
+func writeError(w http.ResponseWriter, err error) {
+	s := status.Convert(err)
+	writeMessage(w, httpStatus(s.Code()), s.Proto())
+}
+
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
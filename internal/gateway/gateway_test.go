@@ -0,0 +1,333 @@
+package gateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"helpdesk-ratings/internal/certs"
+	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/logging"
+	"helpdesk-ratings/internal/service"
+	"helpdesk-ratings/internal/testutil"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// newTestGateway serves the fixture store over an in-memory gRPC connection
+// and returns the gateway in front of it and the service itself.
+func newTestGateway(t *testing.T, opts ...grpc.ServerOption) (http.Handler, *service.RatingsService) {
+	t.Helper()
+
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	ratingsService := service.NewRatingsService(store)
+
+	conn := testutil.Serve(t, opts, func(server *grpc.Server) {
+		pb.RegisterServiceServer(server, ratingsService)
+	})
+	return NewHandler(pb.NewServiceClient(conn)), ratingsService
+}
+
+func serve(t *testing.T, handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func expectError(t *testing.T, rec *httptest.ResponseRecorder, httpCode int, code codes.Code) {
+	t.Helper()
+
+	if rec.Code != httpCode {
+		t.Fatalf("Expected HTTP %d, got %d: %s", httpCode, rec.Code, rec.Body)
+	}
+	var body struct {
+		Code    codes.Code `json:"code"`
+		Message string     `json:"message"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode error: %v", err)
+	}
+	if body.Code != code || body.Message == "" {
+		t.Fatalf("Expected %v with a message, got %s", code, rec.Body)
+	}
+}
+
+func TestGetAggregatedScores(t *testing.T) {
+	handler, ratingsService := newTestGateway(t)
+
+	rec := serve(t, handler, http.MethodGet, "/v1/scores/aggregated?start_date=2025-01-01T00:00:00Z&end_date=2025-01-03T23:59:59Z&granularity=GRANULARITY_DAILY&use_current_weights", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("Expected a JSON response, got %q", rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `"start_date"`) {
+		t.Fatalf("Expected proto field names, got %s", rec.Body)
+	}
+
+	got := &pb.AggregatedScoresResponse{}
+	if err := protojson.Unmarshal(rec.Body.Bytes(), got); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want, err := ratingsService.GetAggregatedScores(context.Background(), &pb.AggregatedScoresRequest{
+		StartDate:         timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:           timestamppb.New(time.Date(2025, 1, 3, 23, 59, 59, 0, time.UTC)),
+		Granularity:       pb.Granularity_GRANULARITY_DAILY,
+		UseCurrentWeights: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Fatalf("Expected the gRPC response\n%v\ngot\n%v", want, got)
+	}
+}
+
+func TestExportAggregatedScores(t *testing.T) {
+	handler, _ := newTestGateway(t)
+
+	rec := serve(t, handler, http.MethodGet, "/v1/scores/aggregated.csv?start_date=2025-01-01T00:00:00Z&end_date=2025-01-04T23:59:59Z&granularity=GRANULARITY_DAILY", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP 200, got %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/csv; charset=utf-8" {
+		t.Fatalf("Expected a CSV response, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != "attachment; filename=scores_2025-01-01_2025-01-04.csv" {
+		t.Fatalf("Unexpected Content-Disposition %q", got)
+	}
+	if !strings.HasPrefix(rec.Body.String(), "Category,Ratings,2025-01-01,2025-01-02,2025-01-03,2025-01-04,Score\n") {
+		t.Fatalf("Unexpected CSV:\n%s", rec.Body)
+	}
+
+	rec = serve(t, handler, http.MethodGet, "/v1/scores/aggregated.csv?start_date=2025-01-01T00:00:00Z", "")
+	expectError(t, rec, http.StatusBadRequest, codes.InvalidArgument)
+}
+
+func TestQueryParameters(t *testing.T) {
+	handler, _ := newTestGateway(t)
+
+	rec := serve(t, handler, http.MethodGet, "/v1/scores/overall?startDate=2025-01-01T00:00:00Z&end_date=2025-01-01T23:59:59Z", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected JSON names to be accepted, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(t, handler, http.MethodGet, "/v1/scores/aggregated?start_date=2025-01-01T00:00:00Z&end_date=2025-01-01T23:59:59Z&granularity=2", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected enum numbers to be accepted, got %d: %s", rec.Code, rec.Body)
+	}
+
+	tests := map[string]string{
+		"missing end_date": "/v1/scores/overall?start_date=2025-01-01T00:00:00Z",
+		"unknown field":    "/v1/scores/overall?start_date=2025-01-01T00:00:00Z&end_date=2025-01-02T00:00:00Z&foo=1",
+		"invalid date":     "/v1/scores/overall?start_date=yesterday&end_date=2025-01-02T00:00:00Z",
+		"repeated field":   "/v1/scores/overall?start_date=2025-01-01T00:00:00Z&start_date=2025-01-02T00:00:00Z",
+		"invalid enum":     "/v1/scores/aggregated?start_date=2025-01-01T00:00:00Z&end_date=2025-01-02T00:00:00Z&granularity=YEARLY",
+	}
+	for name, target := range tests {
+		t.Run(name, func(t *testing.T) {
+			expectError(t, serve(t, handler, http.MethodGet, target, ""), http.StatusBadRequest, codes.InvalidArgument)
+		})
+	}
+}
+
+func TestPathParameters(t *testing.T) {
+	handler, _ := newTestGateway(t)
+
+	rec := serve(t, handler, http.MethodGet, "/v1/agents/3/scores/aggregated?start_date=2025-01-01T00:00:00Z&end_date=2025-01-03T23:59:59Z", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = serve(t, handler, http.MethodGet, "/v1/agents/abc/scores/aggregated?start_date=2025-01-01T00:00:00Z&end_date=2025-01-03T23:59:59Z", "")
+	expectError(t, rec, http.StatusBadRequest, codes.InvalidArgument)
+}
+
+func TestCategories(t *testing.T) {
+	handler, _ := newTestGateway(t)
+
+	rec := serve(t, handler, http.MethodPost, "/v1/categories", `{"name": "Empathy", "weight": 0.8}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP 200, got %d: %s", rec.Code, rec.Body)
+	}
+	created := &pb.Category{}
+	if err := protojson.Unmarshal(rec.Body.Bytes(), created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// The path parameter wins over the id in the body.
+	rec = serve(t, handler, http.MethodPatch, "/v1/categories/"+strconv.FormatInt(created.Id, 10), `{"id": "1", "name": "Politeness"}`)
+	renamed := &pb.Category{}
+	if err := protojson.Unmarshal(rec.Body.Bytes(), renamed); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if rec.Code != http.StatusOK || renamed.Id != created.Id || renamed.Name != "Politeness" {
+		t.Fatalf("Expected category %d to be renamed, got %d: %s", created.Id, rec.Code, rec.Body)
+	}
+
+	expectError(t, serve(t, handler, http.MethodPost, "/v1/categories", `{"name": "Politeness", "weight": 1}`), http.StatusConflict, codes.AlreadyExists)
+	expectError(t, serve(t, handler, http.MethodPost, "/v1/categories", `{"name": `), http.StatusBadRequest, codes.InvalidArgument)
+	tooLarge := `{"name": "` + strings.Repeat("a", MAX_BODY_SIZE) + `", "weight": 1}`
+	expectError(t, serve(t, handler, http.MethodPost, "/v1/categories", tooLarge), http.StatusRequestEntityTooLarge, codes.InvalidArgument)
+	expectError(t, serve(t, handler, http.MethodPost, "/v1/categories/99/retire", ""), http.StatusNotFound, codes.NotFound)
+
+	if rec := serve(t, handler, http.MethodPost, "/v1/categories/1/retire", ""); rec.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP 200, got %d: %s", rec.Code, rec.Body)
+	}
+	expectError(t, serve(t, handler, http.MethodPost, "/v1/categories/1/retire", ""), http.StatusBadRequest, codes.FailedPrecondition)
+
+	if rec := serve(t, handler, http.MethodDelete, "/v1/categories/1", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected HTTP 405, got %d", rec.Code)
+	}
+}
+
+func TestForwardsMetadata(t *testing.T) {
+	var md metadata.MD
+	handler, _ := newTestGateway(t, grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		md, _ = metadata.FromIncomingContext(ctx)
+		return next(ctx, req)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Grpc-Metadata-X-Request-Id", "42")
+	req.Header.Set("X-Other", "ignored")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := md.Get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+		t.Fatalf("Expected the authorization header to be forwarded, got %v", md)
+	}
+	if got := md.Get("x-request-id"); len(got) != 1 || got[0] != "42" {
+		t.Fatalf("Expected the prefixed header to be forwarded, got %v", md)
+	}
+	if got := md.Get("x-other"); len(got) != 0 {
+		t.Fatalf("Expected other headers to be dropped, got %v", md)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	handler, _ := newTestGateway(t,
+		grpc.UnaryInterceptor(logging.UnaryServerInterceptor()))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+	req.Header.Set("X-Request-Id", "42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Request-Id"); got != "42" {
+		t.Fatalf("Expected the request ID to be returned, got %q", got)
+	}
+
+	// Failed calls return the generated ID too.
+	rec = serve(t, handler, http.MethodGet, "/v1/scores/aggregated", "")
+	expectError(t, rec, http.StatusBadRequest, codes.InvalidArgument)
+	if got := rec.Header().Get("X-Request-Id"); got == "" {
+		t.Fatalf("Expected a generated request ID, got none")
+	}
+}
+
+// TestClientCertificate checks that the handlers see the certificate the
+// gateway verified, and not one sent as metadata by the client.
+func TestClientCertificate(t *testing.T) {
+	clients := make(chan string, 1)
+	handler, _ := newTestGateway(t,
+		grpc.ChainUnaryInterceptor(certs.ForwardedUnaryServerInterceptor(),
+			func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+				cert, ok := certs.ClientCertificate(ctx)
+				if !ok {
+					clients <- ""
+				} else {
+					clients <- cert.Subject.CommonName
+				}
+				return handler(ctx, req)
+			}))
+	client := selfSigned(t, "reporting")
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{client}}}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if name := <-clients; name != "reporting" {
+		t.Fatalf("Expected the reporting client, got %q", name)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/categories", nil)
+	req.Header.Set(METADATA_HEADER_PREFIX+certs.FORWARDED_CERTIFICATE_METADATA, string(selfSigned(t, "spoofed").Raw))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if name := <-clients; name != "" {
+		t.Fatalf("Expected no client certificate, got %q", name)
+	}
+}
+
+func selfSigned(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	return cert
+}
+
+func TestOpenAPIDescribesRoutes(t *testing.T) {
+	handler, _ := newTestGateway(t)
+
+	rec := serve(t, handler, http.MethodGet, "/openapi.json", "")
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to decode the OpenAPI document: %v", err)
+	}
+
+	described := routes(pb.NewServiceClient(nil))
+	for _, route := range described {
+		if _, ok := doc.Paths[route.path][strings.ToLower(route.method)]; !ok {
+			t.Errorf("%s %s is not described", route.method, route.path)
+		}
+	}
+	operations := 0
+	for _, methods := range doc.Paths {
+		operations += len(methods)
+	}
+	if operations != len(described) {
+		t.Errorf("Expected %d operations, got %d", len(described), operations)
+	}
+}
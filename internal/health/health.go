@@ -0,0 +1,101 @@
+// Package health serves grpc.health.v1 with a status that follows a
+// periodic check of the database.
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Checker reports NOT_SERVING until its check first passes, and whenever
+// the latest check failed. The status is set for the whole server, the empty
+// service name, and for each of the given services.
+type Checker struct {
+	server   *health.Server
+	services []string
+	check    func(context.Context) error
+	interval time.Duration
+
+	mu       sync.Mutex
+	status   healthpb.HealthCheckResponse_ServingStatus
+	shutdown bool
+}
+
+// NewChecker runs check every interval once Run is called. A check that
+// takes longer than interval fails.
+func NewChecker(check func(context.Context) error, interval time.Duration, services ...string) *Checker {
+	c := &Checker{
+		server:   health.NewServer(),
+		services: append([]string{""}, services...),
+		check:    check,
+		interval: interval,
+	}
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return c
+}
+
+// Register adds the health service to s.
+func (c *Checker) Register(s grpc.ServiceRegistrar) {
+	healthpb.RegisterHealthServer(s, c.server)
+}
+
+// Run checks until ctx is done.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.Update(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Update runs the check once and sets the status from its result. Changes of
+// the status are logged.
+func (c *Checker) Update(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.interval)
+	defer cancel()
+	err := c.check(ctx)
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status == c.status || c.shutdown {
+		return err
+	}
+	c.status = status
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+	if err != nil {
+		slog.Error("Database is unavailable, health is NOT_SERVING", "error", err)
+	} else {
+		slog.Info("Database is available, health is SERVING")
+	}
+	return err
+}
+
+// Shutdown reports NOT_SERVING for good, later checks do not change the
+// status.
+func (c *Checker) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shutdown = true
+	c.server.Shutdown()
+}
@@ -0,0 +1,111 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"helpdesk-ratings/internal/testutil"
+)
+
+// newTestClient serves the health service of c over an in-memory gRPC
+// connection.
+func newTestClient(t *testing.T, c *Checker) healthpb.HealthClient {
+	t.Helper()
+
+	return healthpb.NewHealthClient(testutil.Serve(t, nil, func(server *grpc.Server) { c.Register(server) }))
+}
+
+func expectStatus(t *testing.T, client healthpb.HealthClient, service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Status != expected {
+		t.Fatalf("Expected %v for %q, got %v", expected, service, resp.Status)
+	}
+}
+
+func TestChecker(t *testing.T) {
+	// Update runs the check on the calling goroutine.
+	failure := errors.New("database is locked")
+	check := func(context.Context) error { return failure }
+
+	c := NewChecker(check, time.Second, "ratings.Service")
+	client := newTestClient(t, c)
+	ctx := context.Background()
+
+	// Nothing is served before the first check.
+	expectStatus(t, client, "", healthpb.HealthCheckResponse_NOT_SERVING)
+	expectStatus(t, client, "ratings.Service", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if err := c.Update(ctx); err == nil {
+		t.Fatalf("Expected the check to fail")
+	}
+	expectStatus(t, client, "ratings.Service", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	failure = nil
+	if err := c.Update(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectStatus(t, client, "", healthpb.HealthCheckResponse_SERVING)
+	expectStatus(t, client, "ratings.Service", healthpb.HealthCheckResponse_SERVING)
+
+	failure = errors.New("disk I/O error")
+	c.Update(ctx)
+	expectStatus(t, client, "", healthpb.HealthCheckResponse_NOT_SERVING)
+	expectStatus(t, client, "ratings.Service", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	failure = nil
+	c.Shutdown()
+	c.Update(ctx)
+	expectStatus(t, client, "", healthpb.HealthCheckResponse_NOT_SERVING)
+	expectStatus(t, client, "ratings.Service", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "other.Service"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected NotFound for an unknown service, got %v", err)
+	}
+}
+
+func TestCheckerRun(t *testing.T) {
+	checks := make(chan struct{}, 10)
+	check := func(ctx context.Context) error {
+		checks <- struct{}{}
+		return nil
+	}
+
+	c := NewChecker(check, 10*time.Millisecond)
+	client := newTestClient(t, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx)
+		close(done)
+	}()
+
+	for range 2 {
+		select {
+		case <-checks:
+		case <-time.After(time.Second):
+			t.Fatalf("Expected periodic checks")
+		}
+	}
+	expectStatus(t, client, "", healthpb.HealthCheckResponse_SERVING)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Run to return once the context is done")
+	}
+}
@@ -0,0 +1,191 @@
+// Package logging writes JSON logs with log/slog and tags every line written
+// during an RPC with the request ID, the method and the trace ID of the call.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/certs"
+	"helpdesk-ratings/internal/config"
+)
+
+const (
+	REQUEST_ID_HEADER     = "x-request-id"
+	MAX_REQUEST_ID_LENGTH = 128
+	HEALTH_SERVICE        = "/grpc.health.v1.Health/"
+)
+
+type callKey struct{}
+
+// call is what the interceptors know about the RPC in a context.
+type call struct {
+	requestID string
+	method    string
+}
+
+// Setup makes a JSON handler at the configured level, one of debug, info,
+// warn or error, the default logger. Lines of the log package go to the same
+// handler.
+func Setup(cfg config.LoggingConfig) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return fmt.Errorf("invalid LOG_LEVEL %q: %w", cfg.Level, err)
+	}
+	slog.SetDefault(slog.New(NewHandler(os.Stderr, level)))
+	return nil
+}
+
+// NewHandler writes JSON lines to w, with the call attributes of the context
+// the line is logged with.
+func NewHandler(w io.Writer, level slog.Leveler) slog.Handler {
+	return contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})}
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if c, ok := ctx.Value(callKey{}).(call); ok {
+		record.AddAttrs(slog.String("request_id", c.requestID), slog.String("method", c.method))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.HasTraceID() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// RequestID returns the ID of the request ctx belongs to, or an empty string
+// outside of an RPC.
+func RequestID(ctx context.Context) string {
+	c, _ := ctx.Value(callKey{}).(call)
+	return c.requestID
+}
+
+// UnaryServerInterceptor tags the context of the call with its request ID,
+// sends the ID back in the response headers and logs the call once it is
+// handled.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = withCall(ctx, info.FullMethod)
+		if err := grpc.SetHeader(ctx, metadata.Pairs(REQUEST_ID_HEADER, RequestID(ctx))); err != nil {
+			slog.WarnContext(ctx, "Failed to set the request ID header", "error", err)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withCall(stream.Context(), info.FullMethod)
+		if err := stream.SetHeader(metadata.Pairs(REQUEST_ID_HEADER, RequestID(ctx))); err != nil {
+			slog.WarnContext(ctx, "Failed to set the request ID header", "error", err)
+		}
+
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+		logCall(ctx, start, err)
+		return err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// withCall keeps the request ID of the incoming metadata, or generates one
+// when it is missing or unusable.
+func withCall(ctx context.Context, method string) context.Context {
+	var requestID string
+	if values := metadata.ValueFromIncomingContext(ctx, REQUEST_ID_HEADER); len(values) > 0 && validRequestID(values[0]) {
+		requestID = values[0]
+	} else {
+		requestID = newRequestID()
+	}
+	return context.WithValue(ctx, callKey{}, call{requestID: requestID, method: method})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > MAX_REQUEST_ID_LENGTH {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// logCall logs a handled call, with the common name of the mTLS client if
+// any, at a level that follows its status: errors for the codes that point
+// at the server, warnings for the other failures. Probes call the health
+// service every few seconds, its successful calls are only logged at the
+// debug level.
+func logCall(ctx context.Context, start time.Time, err error) {
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("code", code.String()),
+		slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+	if cert, ok := certs.ClientCertificate(ctx); ok {
+		attrs = append(attrs, slog.String("client", cert.Subject.CommonName))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+		if c, _ := ctx.Value(callKey{}).(call); strings.HasPrefix(c.method, HEALTH_SERVICE) {
+			level = slog.LevelDebug
+		}
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable, codes.Unimplemented, codes.DeadlineExceeded:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	slog.LogAttrs(ctx, level, "Finished call", attrs...)
+}
@@ -0,0 +1,205 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"helpdesk-ratings/internal/config"
+	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/service"
+	"helpdesk-ratings/internal/testutil"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// syncBuffer is written by the server goroutines and read by the test.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// lines decodes the JSON lines written so far.
+func (b *syncBuffer) lines(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("Expected a JSON line, got %q: %v", line, err)
+		}
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+// captureLogs makes the default logger write to the returned buffer for the
+// duration of the test.
+func captureLogs(t *testing.T) *syncBuffer {
+	t.Helper()
+
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	logs := &syncBuffer{}
+	slog.SetDefault(slog.New(NewHandler(logs, slog.LevelDebug)))
+	return logs
+}
+
+// newTestClient serves the fixture store with the logging interceptors over
+// an in-memory gRPC connection.
+func newTestClient(t *testing.T) pb.ServiceClient {
+	t.Helper()
+
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	conn := testutil.Serve(t, []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor()),
+	}, func(server *grpc.Server) {
+		pb.RegisterServiceServer(server, service.NewRatingsService(store))
+	})
+	return pb.NewServiceClient(conn)
+}
+
+// expectLine finds the line with msg and checks its fields.
+func expectLine(t *testing.T, lines []map[string]any, msg string, fields map[string]any) map[string]any {
+	t.Helper()
+
+	for _, line := range lines {
+		if line["msg"] != msg {
+			continue
+		}
+		for key, value := range fields {
+			if line[key] != value {
+				t.Fatalf("Expected %s=%v in %v", key, value, line)
+			}
+		}
+		return line
+	}
+	t.Fatalf("Expected a %q line in %v", msg, lines)
+	return nil
+}
+
+var aggregatedScoresRequest = &pb.AggregatedScoresRequest{
+	StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+	EndDate:   timestamppb.New(time.Date(2025, 1, 3, 23, 59, 59, 0, time.UTC)),
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	logs := captureLogs(t)
+	client := newTestClient(t)
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), REQUEST_ID_HEADER, "request-1")
+	if _, err := client.GetAggregatedScores(ctx, aggregatedScoresRequest, grpc.Header(&header)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := header.Get(REQUEST_ID_HEADER); len(got) != 1 || got[0] != "request-1" {
+		t.Fatalf("Expected the incoming request ID to be returned, got %v", header)
+	}
+
+	method := "/ratings.Service/GetAggregatedScores"
+	lines := logs.lines(t)
+	expectLine(t, lines, "Processing GetAggregatedScores request", map[string]any{
+		"level": "INFO", "request_id": "request-1", "method": method, "start_date": "2025-01-01T00:00:00Z",
+	})
+	finished := expectLine(t, lines, "Finished call", map[string]any{
+		"level": "INFO", "request_id": "request-1", "method": method, "code": "OK", "peer": "bufconn",
+	})
+	if _, ok := finished["duration_ms"].(float64); !ok {
+		t.Fatalf("Expected a duration in %v", finished)
+	}
+}
+
+func TestUnaryServerInterceptorGeneratesRequestID(t *testing.T) {
+	logs := captureLogs(t)
+	client := newTestClient(t)
+
+	// A request ID that cannot be logged safely is replaced.
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), REQUEST_ID_HEADER, "two words")
+	_, err := client.GetAggregatedScores(ctx, &pb.AggregatedScoresRequest{}, grpc.Header(&header))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	requestID := header.Get(REQUEST_ID_HEADER)
+	if len(requestID) != 1 || len(requestID[0]) != 32 {
+		t.Fatalf("Expected a generated request ID, got %v", header)
+	}
+
+	lines := logs.lines(t)
+	expectLine(t, lines, "Invalid date range", map[string]any{"level": "WARN", "request_id": requestID[0]})
+	expectLine(t, lines, "Finished call", map[string]any{
+		"level": "WARN", "request_id": requestID[0], "code": "InvalidArgument",
+		"error": "start_date and end_date are required, and start_date cannot be after end_date",
+	})
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	logs := captureLogs(t)
+	client := newTestClient(t)
+
+	stream, err := client.StreamAggregatedScores(context.Background(), aggregatedScoresRequest)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	header, err := stream.Header()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	requestID := header.Get(REQUEST_ID_HEADER)
+	if len(requestID) != 1 || requestID[0] == "" {
+		t.Fatalf("Expected a request ID, got %v", header)
+	}
+
+	method := "/ratings.Service/StreamAggregatedScores"
+	lines := logs.lines(t)
+	expectLine(t, lines, "Streaming report", map[string]any{"request_id": requestID[0], "method": method})
+	expectLine(t, lines, "Finished call", map[string]any{"request_id": requestID[0], "method": method, "code": "OK"})
+}
+
+func TestSetup(t *testing.T) {
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	if err := Setup(config.LoggingConfig{Level: "warn"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if slog.Default().Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatalf("Expected info lines to be dropped at the warn level")
+	}
+	if err := Setup(config.LoggingConfig{Level: "verbose"}); err == nil {
+		t.Fatalf("Expected an error for an unknown level")
+	}
+}
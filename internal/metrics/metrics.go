@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/database"
+)
+
+const (
+	UNARY          = "unary"
+	SERVER_STREAM  = "server_stream"
+	CLIENT_STREAM  = "client_stream"
+	BIDI_STREAM    = "bidi_stream"
+	QUERY_OK       = "ok"
+	QUERY_ERROR    = "error"
+	UNKNOWN_METHOD = "unknown"
+)
+
+// Metrics collects the gRPC server, repository and connection pool metrics
+// in its own registry, together with the Go runtime and process metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	handled       *prometheus.CounterVec
+	handling      *prometheus.HistogramVec
+	queryDuration *prometheus.HistogramVec
+	queryRows     *prometheus.HistogramVec
+}
+
+var _ database.QueryObserver = (*Metrics)(nil)
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "RPCs completed on the server, by method and status code.",
+		}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"}),
+		handling: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time the server took to handle an RPC, by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_type", "grpc_service", "grpc_method"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_query_duration_seconds",
+			Help:    "Time a repository query took, by query and result.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"query", "result"}),
+		queryRows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_query_rows",
+			Help:    "Rows a successful repository query scanned, by query.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{"query"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.handled,
+		m.handling,
+		m.queryDuration,
+		m.queryRows,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observeCall(UNARY, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		m.observeCall(streamType(info), info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (m *Metrics) observeCall(callType, fullMethod string, start time.Time, err error) {
+	service, method := splitMethod(fullMethod)
+	m.handled.WithLabelValues(callType, service, method, status.Code(err).String()).Inc()
+	m.handling.WithLabelValues(callType, service, method).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) ObserveQuery(query string, duration time.Duration, rows int, err error) {
+	if err != nil {
+		m.queryDuration.WithLabelValues(query, QUERY_ERROR).Observe(duration.Seconds())
+		return
+	}
+	m.queryDuration.WithLabelValues(query, QUERY_OK).Observe(duration.Seconds())
+	m.queryRows.WithLabelValues(query).Observe(float64(rows))
+}
+
+// RegisterDBStats exports the connection pool statistics returned by stats,
+// read on every scrape.
+func (m *Metrics) RegisterDBStats(stats func() sql.DBStats) {
+	gauge := func(name, help string, value func(sql.DBStats) int) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return float64(value(stats()))
+		})
+	}
+	counter := func(name, help string, value func(sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, func() float64 {
+			return value(stats())
+		})
+	}
+
+	m.registry.MustRegister(
+		gauge("db_max_open_connections", "Maximum number of open connections to the database.",
+			func(s sql.DBStats) int { return s.MaxOpenConnections }),
+		gauge("db_open_connections", "Established connections, in use and idle.",
+			func(s sql.DBStats) int { return s.OpenConnections }),
+		gauge("db_in_use_connections", "Connections currently in use.",
+			func(s sql.DBStats) int { return s.InUse }),
+		gauge("db_idle_connections", "Idle connections.",
+			func(s sql.DBStats) int { return s.Idle }),
+		counter("db_wait_count_total", "Connections waited for.",
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		counter("db_wait_duration_seconds_total", "Time blocked waiting for a connection.",
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+		counter("db_max_idle_closed_total", "Connections closed due to the idle connection limit.",
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }),
+		counter("db_max_idle_time_closed_total", "Connections closed due to the idle time limit.",
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }),
+		counter("db_max_lifetime_closed_total", "Connections closed due to the connection lifetime limit.",
+			func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }),
+	)
+}
+
+// RegisterCacheStats exports the query cache statistics returned by stats,
+// read on every scrape.
+func (m *Metrics) RegisterCacheStats(stats func() database.CacheStats) {
+	counter := func(name, help string, value func(database.CacheStats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, func() float64 {
+			return float64(value(stats()))
+		})
+	}
+
+	m.registry.MustRegister(
+		counter("query_cache_hits_total", "Lookups answered from the query cache.",
+			func(s database.CacheStats) uint64 { return s.Hits }),
+		counter("query_cache_misses_total", "Lookups that reached the store.",
+			func(s database.CacheStats) uint64 { return s.Misses }),
+		counter("query_cache_evictions_total", "Entries evicted to stay within the cache size.",
+			func(s database.CacheStats) uint64 { return s.Evictions }),
+		counter("query_cache_invalidations_total", "Times the cache was cleared after a write.",
+			func(s database.CacheStats) uint64 { return s.Invalidations }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: "query_cache_entries", Help: "Results currently cached."}, func() float64 {
+			return float64(stats().Entries)
+		}),
+	)
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return BIDI_STREAM
+	case info.IsClientStream:
+		return CLIENT_STREAM
+	default:
+		return SERVER_STREAM
+	}
+}
+
+// splitMethod splits /package.Service/Method into the service and the method.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, found := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !found {
+		return UNKNOWN_METHOD, UNKNOWN_METHOD
+	}
+	return service, method
+}
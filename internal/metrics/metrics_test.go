@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/service"
+	grpctest "helpdesk-ratings/internal/testutil"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// newTestClient serves the fixture store with the metrics interceptors over
+// an in-memory gRPC connection.
+func newTestClient(t *testing.T, m *Metrics) pb.ServiceClient {
+	t.Helper()
+
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	conn := grpctest.Serve(t, []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(m.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(m.StreamServerInterceptor()),
+	}, func(server *grpc.Server) {
+		pb.RegisterServiceServer(server, service.NewRatingsService(store))
+	})
+	return pb.NewServiceClient(conn)
+}
+
+func TestServerMetrics(t *testing.T) {
+	m := New()
+	client := newTestClient(t, m)
+	ctx := context.Background()
+
+	req := &pb.AggregatedScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 3, 23, 59, 59, 0, time.UTC)),
+	}
+	for range 2 {
+		if _, err := client.GetAggregatedScores(ctx, req); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	_, err := client.GetAggregatedScores(ctx, &pb.AggregatedScoresRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+
+	stream, err := client.StreamAggregatedScores(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	tests := []struct {
+		labels   []string
+		expected float64
+	}{
+		{[]string{UNARY, "ratings.Service", "GetAggregatedScores", "OK"}, 2},
+		{[]string{UNARY, "ratings.Service", "GetAggregatedScores", "InvalidArgument"}, 1},
+		{[]string{SERVER_STREAM, "ratings.Service", "StreamAggregatedScores", "OK"}, 1},
+	}
+	for _, test := range tests {
+		if got := testutil.ToFloat64(m.handled.WithLabelValues(test.labels...)); got != test.expected {
+			t.Fatalf("Expected %v calls for %v, got %v", test.expected, test.labels, got)
+		}
+	}
+	if count := testutil.CollectAndCount(m.handling); count != 2 {
+		t.Fatalf("Expected latency histograms for 2 methods, got %d", count)
+	}
+}
+
+func TestQueryMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "database.db")
+	migrator, err := database.NewMigrator(path)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	migrator.Close()
+
+	repo, err := database.NewRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	m := New()
+	m.RegisterDBStats(repo.DBStats)
+	store := repo.WithQueryObserver(m)
+
+	category, err := store.CreateCategory("Spelling", 1)
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	rating := database.Rating{Value: 4, TicketID: 1, CategoryID: category.ID, ReviewerID: 1, RevieweeID: 2, CreatedAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)}
+	if _, err := store.InsertRatings([]database.Rating{rating, rating}); err != nil {
+		t.Fatalf("Failed to insert ratings: %v", err)
+	}
+	if _, err := store.GetWeightedRatings("2025-01-01T00:00:00", "2025-01-01T23:59:59"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.WithCurrentWeights().GetOverallScore("2025-01-01T00:00:00", "2025-01-01T23:59:59"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.GetWeightedRatings("invalid", "range"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := store.GetOverallScore("invalid", "range"); err == nil {
+		t.Fatalf("Expected an error for an invalid range")
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, metric := range []string{
+		`repository_query_duration_seconds_count{query="GetOverallScore",result="error"} 1`,
+		`repository_query_duration_seconds_count{query="GetWeightedRatings",result="ok"} 2`,
+		// Both ratings are rolled up into one row.
+		`repository_query_rows_sum{query="GetOverallScore"} 1`,
+		`repository_query_rows_sum{query="GetWeightedRatings"} 2`,
+		`repository_query_rows_bucket{query="GetWeightedRatings",le="1"} 1`,
+		"db_open_connections ",
+		"db_max_open_connections 0",
+		"go_goroutines ",
+	} {
+		if !strings.Contains(rec.Body.String(), metric) {
+			t.Fatalf("Expected %q in\n%s", metric, rec.Body)
+		}
+	}
+}
+
+func TestCacheMetrics(t *testing.T) {
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	cached := database.NewCachedStore(store, time.Minute, 1)
+
+	m := New()
+	m.RegisterCacheStats(cached.Stats)
+
+	for _, endDate := range []string{"2025-01-02T23:59:59", "2025-01-02T23:59:59", "2025-01-03T23:59:59"} {
+		if _, err := cached.GetOverallScore("2025-01-01T00:00:00", endDate); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if _, err := cached.CreateCategory("Empathy", 1); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	for _, metric := range []string{
+		"query_cache_hits_total 1",
+		"query_cache_misses_total 2",
+		"query_cache_evictions_total 1",
+		"query_cache_invalidations_total 1",
+		"query_cache_entries 0",
+	} {
+		if !strings.Contains(rec.Body.String(), metric) {
+			t.Fatalf("Expected %q in\n%s", metric, rec.Body)
+		}
+	}
+}
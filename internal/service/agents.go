@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+func (s *RatingsService) GetAgentScores(ctx context.Context, req *pb.AgentScoresRequest) (*pb.AgentScoresResponse, error) {
+	startTime := req.StartDate.AsTime()
+	endTime := req.EndDate.AsTime()
+	slog.InfoContext(ctx, "Processing GetAgentScores request", "start_date", startTime, "end_date", endTime)
+
+	if req.StartDate == nil || req.EndDate == nil || startTime.After(endTime) {
+		slog.WarnContext(ctx, "Invalid date range", "start_date", startTime, "end_date", endTime)
+		return nil, status.Errorf(codes.InvalidArgument, "start_date and end_date are required, and start_date cannot be after end_date")
+	}
+
+	ratings, err := s.repoFor(ctx, req.UseCurrentWeights).GetAgentRatings(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get agent ratings", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve agent ratings")
+	}
+
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+
+	return &pb.AgentScoresResponse{
+		Agents: CalculateAgentScores(ratings, reportCategories(categories, ratings)),
+	}, nil
+}
+
+func (s *RatingsService) GetAgentAggregatedScores(ctx context.Context, req *pb.AgentAggregatedScoresRequest) (*pb.AggregatedScoresResponse, error) {
+	slog.InfoContext(ctx, "Processing GetAgentAggregatedScores request", "agent_id", req.AgentId, "start_date", req.StartDate.AsTime(), "end_date", req.EndDate.AsTime())
+
+	if req.AgentId <= 0 {
+		slog.WarnContext(ctx, "Invalid agent id", "agent_id", req.AgentId)
+		return nil, status.Errorf(codes.InvalidArgument, "agent_id is required")
+	}
+
+	aggregatedReq := &pb.AggregatedScoresRequest{
+		StartDate:         req.StartDate,
+		EndDate:           req.EndDate,
+		Granularity:       req.Granularity,
+		TimeZone:          req.TimeZone,
+		UseCurrentWeights: req.UseCurrentWeights,
+	}
+
+	report, err := s.rangeReport(ctx, aggregatedReq, req.AgentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AggregatedScoresResponse{
+		Scores: report.scores,
+	}, nil
+}
+
+// CalculateAgentScores expects ratings ordered by reviewee id, as returned by
+// Repository.GetAgentRatings, and returns agents ranked best to worst.
+func CalculateAgentScores(ratings []database.Rating, categories []database.Category) []*pb.AgentScore {
+	agents := []*pb.AgentScore{}
+	if len(ratings) == 0 {
+		return agents
+	}
+
+	current := ratings[0]
+	var all ScoreSum
+	container := createEmptyContainer[ScoreSum]()
+
+	for _, rating := range ratings {
+		if rating.RevieweeID != current.RevieweeID {
+			agents = append(agents, prepareAgentScore(current, categories, all, container))
+			current = rating
+			all = ScoreSum{}
+			container = createEmptyContainer[ScoreSum]()
+		}
+
+		container = scoreByCategory[ScoreSum](container, rating, addScore)
+		all = addScore(all, rating)
+	}
+	agents = append(agents, prepareAgentScore(current, categories, all, container))
+
+	sort.SliceStable(agents, func(i, j int) bool {
+		if agents[i].Score != agents[j].Score {
+			return agents[i].Score > agents[j].Score
+		}
+		return agents[i].Ratings > agents[j].Ratings
+	})
+
+	for i, agent := range agents {
+		agent.Rank = int32(i + 1)
+		if i > 0 && agent.Score == agents[i-1].Score {
+			agent.Rank = agents[i-1].Rank
+		}
+	}
+
+	return agents
+}
+
+func prepareAgentScore(agent database.Rating, categories []database.Category, all ScoreSum, container ScoreContainer[ScoreSum]) *pb.AgentScore {
+	return &pb.AgentScore{
+		AgentId:    agent.RevieweeID,
+		Name:       agent.Reviewee,
+		Score:      all.score(),
+		Ratings:    all.Ratings,
+		Categories: prepareCategoryScores(categories, container),
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+const DEFAULT_OUTLIER_THRESHOLD = 2.0
+
+type ratingStats struct {
+	count int32
+	sum   float64
+}
+
+func (s ratingStats) mean() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.sum / float64(s.count)
+}
+
+func (s *RatingsService) GetReviewerCalibration(ctx context.Context, req *pb.ReviewerCalibrationRequest) (*pb.ReviewerCalibrationResponse, error) {
+	startTime := req.StartDate.AsTime()
+	endTime := req.EndDate.AsTime()
+	slog.InfoContext(ctx, "Processing GetReviewerCalibration request", "start_date", startTime, "end_date", endTime)
+
+	if req.StartDate == nil || req.EndDate == nil || startTime.After(endTime) {
+		slog.WarnContext(ctx, "Invalid date range", "start_date", startTime, "end_date", endTime)
+		return nil, status.Errorf(codes.InvalidArgument, "start_date and end_date are required, and start_date cannot be after end_date")
+	}
+
+	threshold := req.OutlierThreshold
+	if threshold < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "outlier_threshold cannot be negative")
+	}
+	if threshold == 0 {
+		threshold = DEFAULT_OUTLIER_THRESHOLD
+	}
+
+	ratings, err := s.store(ctx).GetReviewerRatings(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get reviewer ratings", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve reviewer ratings")
+	}
+
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+
+	population, reviewers := CalculateReviewerCalibration(ratings, reportCategories(categories, ratings), threshold)
+
+	return &pb.ReviewerCalibrationResponse{
+		Population: population,
+		Reviewers:  reviewers,
+	}, nil
+}
+
+// CalculateReviewerCalibration expects ratings ordered by reviewer id, as
+// returned by Repository.GetReviewerRatings. A reviewer's deviation is
+// measured per category first, so reviewers are not penalised for rating a
+// different mix of categories than everyone else.
+func CalculateReviewerCalibration(ratings []database.Rating, categories []database.Category, threshold float64) ([]*pb.CategoryCalibration, []*pb.ReviewerCalibration) {
+	populationStats := make(map[int64]ratingStats)
+	for _, rating := range ratings {
+		stats := populationStats[rating.CategoryID]
+		stats.count++
+		stats.sum += float64(rating.Value)
+		populationStats[rating.CategoryID] = stats
+	}
+
+	population := make([]*pb.CategoryCalibration, 0, len(categories))
+	for _, category := range categories {
+		stats := populationStats[category.ID]
+		population = append(population, &pb.CategoryCalibration{
+			CategoryId: category.ID,
+			Name:       category.Name,
+			Ratings:    stats.count,
+			MeanRating: stats.mean(),
+		})
+	}
+
+	reviewers := []*pb.ReviewerCalibration{}
+	for start := 0; start < len(ratings); {
+		end := start
+		for end < len(ratings) && ratings[end].ReviewerID == ratings[start].ReviewerID {
+			end++
+		}
+		reviewers = append(reviewers, prepareReviewerCalibration(ratings[start:end], categories, populationStats))
+		start = end
+	}
+
+	flagOutliers(reviewers, threshold)
+
+	return population, reviewers
+}
+
+func prepareReviewerCalibration(ratings []database.Rating, categories []database.Category, populationStats map[int64]ratingStats) *pb.ReviewerCalibration {
+	reviewerStats := make(map[int64]ratingStats)
+	var total ratingStats
+	var deviationSum float64
+
+	for _, rating := range ratings {
+		stats := reviewerStats[rating.CategoryID]
+		stats.count++
+		stats.sum += float64(rating.Value)
+		reviewerStats[rating.CategoryID] = stats
+
+		total.count++
+		total.sum += float64(rating.Value)
+		deviationSum += float64(rating.Value) - populationStats[rating.CategoryID].mean()
+	}
+
+	reviewer := &pb.ReviewerCalibration{
+		ReviewerId: ratings[0].ReviewerID,
+		Name:       ratings[0].Reviewer,
+		Ratings:    total.count,
+		MeanRating: total.mean(),
+		Deviation:  deviationSum / float64(total.count),
+	}
+
+	for _, category := range categories {
+		stats, ok := reviewerStats[category.ID]
+		if !ok {
+			continue
+		}
+		reviewer.Categories = append(reviewer.Categories, &pb.CategoryCalibration{
+			CategoryId: category.ID,
+			Name:       category.Name,
+			Ratings:    stats.count,
+			MeanRating: stats.mean(),
+			Deviation:  stats.mean() - populationStats[category.ID].mean(),
+		})
+	}
+
+	return reviewer
+}
+
+// flagOutliers sets the z-scores of the reviewers' deviations. The reviewers
+// are a sample of everyone who could have rated, so the standard deviation is
+// the sample one, divided by N-1.
+func flagOutliers(reviewers []*pb.ReviewerCalibration, threshold float64) {
+	if len(reviewers) < 2 {
+		return
+	}
+
+	var sum float64
+	for _, reviewer := range reviewers {
+		sum += reviewer.Deviation
+	}
+	mean := sum / float64(len(reviewers))
+
+	var squares float64
+	for _, reviewer := range reviewers {
+		squares += (reviewer.Deviation - mean) * (reviewer.Deviation - mean)
+	}
+	stddev := math.Sqrt(squares / float64(len(reviewers)-1))
+	if stddev == 0 {
+		return
+	}
+
+	for _, reviewer := range reviewers {
+		reviewer.ZScore = (reviewer.Deviation - mean) / stddev
+		reviewer.Outlier = math.Abs(reviewer.ZScore) > threshold
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+func (s *RatingsService) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	slog.InfoContext(ctx, "Processing ListCategories request")
+
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+
+	response := &pb.ListCategoriesResponse{Categories: []*pb.Category{}}
+	for _, category := range categories {
+		if category.Retired() && !req.IncludeRetired {
+			continue
+		}
+
+		result, err := s.prepareCategory(ctx, category)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to get category weights", "category_id", category.ID, "error", err)
+			return nil, status.Errorf(codes.Internal, "Failed to retrieve category weights")
+		}
+		response.Categories = append(response.Categories, result)
+	}
+
+	return response, nil
+}
+
+func (s *RatingsService) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.Category, error) {
+	name := strings.TrimSpace(req.Name)
+	slog.InfoContext(ctx, "Processing CreateCategory request", "name", name)
+
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+	if err := validateWeight(req.Weight); err != nil {
+		return nil, err
+	}
+
+	category, err := s.store(ctx).CreateCategory(name, req.Weight)
+	if err != nil {
+		return nil, categoryError(ctx, "create", err)
+	}
+
+	return s.categoryResponse(ctx, category)
+}
+
+// validateWeight rejects weights that would poison every weighted score.
+func validateWeight(weight float64) error {
+	if math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return status.Errorf(codes.InvalidArgument, "weight must be a finite number")
+	}
+	if weight < 0 {
+		return status.Errorf(codes.InvalidArgument, "weight cannot be negative")
+	}
+	return nil
+}
+
+func (s *RatingsService) RenameCategory(ctx context.Context, req *pb.RenameCategoryRequest) (*pb.Category, error) {
+	name := strings.TrimSpace(req.Name)
+	slog.InfoContext(ctx, "Processing RenameCategory request", "category_id", req.Id, "name", name)
+
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+
+	category, err := s.store(ctx).RenameCategory(req.Id, name)
+	if err != nil {
+		return nil, categoryError(ctx, "rename", err)
+	}
+
+	return s.categoryResponse(ctx, category)
+}
+
+func (s *RatingsService) ReweightCategory(ctx context.Context, req *pb.ReweightCategoryRequest) (*pb.Category, error) {
+	slog.InfoContext(ctx, "Processing ReweightCategory request", "category_id", req.Id, "weight", req.Weight)
+
+	if err := validateWeight(req.Weight); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	effectiveFrom := now
+	if req.EffectiveFrom != nil {
+		effectiveFrom = req.EffectiveFrom.AsTime()
+	}
+	if effectiveFrom.After(now) {
+		return nil, status.Errorf(codes.InvalidArgument, "effective_from cannot be in the future")
+	}
+
+	category, err := s.store(ctx).ReweightCategory(req.Id, req.Weight, effectiveFrom)
+	if err != nil {
+		return nil, categoryError(ctx, "reweight", err)
+	}
+
+	return s.categoryResponse(ctx, category)
+}
+
+func (s *RatingsService) RetireCategory(ctx context.Context, req *pb.RetireCategoryRequest) (*pb.Category, error) {
+	slog.InfoContext(ctx, "Processing RetireCategory request", "category_id", req.Id)
+
+	category, err := s.store(ctx).RetireCategory(req.Id, time.Now())
+	if err != nil {
+		return nil, categoryError(ctx, "retire", err)
+	}
+
+	return s.categoryResponse(ctx, category)
+}
+
+func (s *RatingsService) categoryResponse(ctx context.Context, category database.Category) (*pb.Category, error) {
+	result, err := s.prepareCategory(ctx, category)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get category weights", "category_id", category.ID, "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve category weights")
+	}
+	return result, nil
+}
+
+func (s *RatingsService) prepareCategory(ctx context.Context, category database.Category) (*pb.Category, error) {
+	weights, err := s.store(ctx).GetCategoryWeights(category.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pb.Category{
+		Id:     category.ID,
+		Name:   category.Name,
+		Weight: category.Weight,
+	}
+	if category.RetiredAt != nil {
+		result.RetiredAt = timestamppb.New(*category.RetiredAt)
+	}
+	for _, weight := range weights {
+		result.Weights = append(result.Weights, &pb.CategoryWeight{
+			Weight:        weight.Weight,
+			EffectiveFrom: timestamppb.New(weight.EffectiveFrom),
+		})
+	}
+	return result, nil
+}
+
+func categoryError(ctx context.Context, action string, err error) error {
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return status.Errorf(codes.NotFound, "category not found")
+	case errors.Is(err, database.ErrAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "a category with this name already exists")
+	case errors.Is(err, database.ErrRetired):
+		return status.Errorf(codes.FailedPrecondition, "category is already retired")
+	default:
+		slog.ErrorContext(ctx, "Failed to change category", "action", action, "error", err)
+		return status.Errorf(codes.Internal, "Failed to %s category", action)
+	}
+}
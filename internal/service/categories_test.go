@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+func TestCategoryManagement(t *testing.T) {
+	ratingsService := NewRatingsService(copyDatabase(t))
+	ctx := context.Background()
+
+	created, err := ratingsService.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Tone", Weight: 0.5})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if created.Id == 0 || created.Weight != 0.5 || len(created.Weights) != 1 {
+		t.Fatalf("Unexpected category: %v", created)
+	}
+
+	_, err = ratingsService.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Tone", Weight: 1})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("Expected AlreadyExists, got %v", err)
+	}
+
+	renamed, err := ratingsService.RenameCategory(ctx, &pb.RenameCategoryRequest{Id: created.Id, Name: "Empathy"})
+	if err != nil || renamed.Name != "Empathy" {
+		t.Fatalf("Expected renamed category, got %v, %v", renamed, err)
+	}
+
+	_, err = ratingsService.SubmitRating(ctx, &pb.SubmitRatingRequest{TicketId: 1, CategoryId: created.Id, ReviewerId: 1, RevieweeId: 7, Value: 4})
+	if err != nil {
+		t.Fatalf("Expected rating for new category to be accepted, got %v", err)
+	}
+
+	retired, err := ratingsService.RetireCategory(ctx, &pb.RetireCategoryRequest{Id: created.Id})
+	if err != nil || retired.RetiredAt == nil {
+		t.Fatalf("Expected retired category, got %v, %v", retired, err)
+	}
+
+	_, err = ratingsService.RetireCategory(ctx, &pb.RetireCategoryRequest{Id: created.Id})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Expected FailedPrecondition, got %v", err)
+	}
+
+	_, err = ratingsService.SubmitRating(ctx, &pb.SubmitRatingRequest{TicketId: 1, CategoryId: created.Id, ReviewerId: 1, RevieweeId: 7, Value: 4})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected rating for retired category to be rejected, got %v", err)
+	}
+
+	active, err := ratingsService.ListCategories(ctx, &pb.ListCategoriesRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	all, err := ratingsService.ListCategories(ctx, &pb.ListCategoriesRequest{IncludeRetired: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(all.Categories) != len(active.Categories)+1 {
+		t.Fatalf("Expected retired category only with include_retired, got %d and %d", len(active.Categories), len(all.Categories))
+	}
+
+	_, err = ratingsService.RenameCategory(ctx, &pb.RenameCategoryRequest{Id: 999, Name: "Missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestCategoryWeightValidation(t *testing.T) {
+	ratingsService := NewRatingsService(copyDatabase(t))
+	ctx := context.Background()
+
+	for _, weight := range []float64{-0.5, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := ratingsService.CreateCategory(ctx, &pb.CreateCategoryRequest{Name: "Tone", Weight: weight})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument creating a category with weight %v, got %v", weight, err)
+		}
+
+		_, err = ratingsService.ReweightCategory(ctx, &pb.ReweightCategoryRequest{Id: 1, Weight: weight})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument reweighting to %v, got %v", weight, err)
+		}
+	}
+}
+
+func TestReweightCategoryKeepsHistoricalScores(t *testing.T) {
+	ratingsService := NewRatingsService(copyDatabase(t))
+	ctx := context.Background()
+
+	january := &pb.OverallScoreRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)),
+	}
+
+	before, err := ratingsService.GetOverallScore(ctx, january)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	categories, err := ratingsService.ListCategories(ctx, &pb.ListCategoriesRequest{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	grammar := categories.Categories[1]
+
+	reweighted, err := ratingsService.ReweightCategory(ctx, &pb.ReweightCategoryRequest{
+		Id:            grammar.Id,
+		Weight:        5,
+		EffectiveFrom: timestamppb.New(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if reweighted.Weight != 5 || len(reweighted.Weights) != 2 {
+		t.Fatalf("Unexpected reweighted category: %v", reweighted)
+	}
+
+	after, err := ratingsService.GetOverallScore(ctx, january)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if after.OverallScore != before.OverallScore {
+		t.Fatalf("Expected historical score %v to stay the same, got %v", before.OverallScore, after.OverallScore)
+	}
+
+	january.UseCurrentWeights = true
+	current, err := ratingsService.GetOverallScore(ctx, january)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if current.OverallScore == before.OverallScore {
+		t.Fatalf("Expected current weights to change the score %v", before.OverallScore)
+	}
+
+	_, err = ratingsService.ReweightCategory(ctx, &pb.ReweightCategoryRequest{
+		Id:            grammar.Id,
+		Weight:        1,
+		EffectiveFrom: timestamppb.New(time.Now().Add(time.Hour)),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument for future effective_from, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// periodScores are the scores of one period of a comparison, keyed by
+// category id. rated is false when the period has no ratings.
+type periodScores struct {
+	rated      bool
+	overall    float32
+	categories map[int64]int32
+	names      map[int64]string
+	order      []int64
+}
+
+// overallScore returns nil for a period without ratings.
+func (p *periodScores) overallScore() *float32 {
+	if !p.rated {
+		return nil
+	}
+	return &p.overall
+}
+
+// categoryScore returns nil when the category was not rated in the period.
+func (p *periodScores) categoryScore(id int64) *float32 {
+	score, ok := p.categories[id]
+	if !ok {
+		return nil
+	}
+	value := float32(score)
+	return &value
+}
+
+func (s *RatingsService) CompareScores(ctx context.Context, req *pb.CompareScoresRequest) (*pb.CompareScoresResponse, error) {
+	startTime := req.StartDate.AsTime()
+	endTime := req.EndDate.AsTime()
+	slog.InfoContext(ctx, "Processing CompareScores request", "start_date", startTime, "end_date", endTime)
+
+	if req.StartDate == nil || req.EndDate == nil || startTime.After(endTime) {
+		slog.WarnContext(ctx, "Invalid date range", "start_date", startTime, "end_date", endTime)
+		return nil, status.Errorf(codes.InvalidArgument, "start_date and end_date are required, and start_date cannot be after end_date")
+	}
+
+	if (req.ComparisonStartDate == nil) != (req.ComparisonEndDate == nil) {
+		return nil, status.Errorf(codes.InvalidArgument, "comparison_start_date and comparison_end_date must be set together")
+	}
+
+	comparisonStart, comparisonEnd := previousPeriod(startTime, endTime)
+	if req.ComparisonStartDate != nil {
+		comparisonStart = req.ComparisonStartDate.AsTime()
+		comparisonEnd = req.ComparisonEndDate.AsTime()
+		if comparisonStart.After(comparisonEnd) {
+			slog.WarnContext(ctx, "Invalid comparison date range", "start_date", comparisonStart, "end_date", comparisonEnd)
+			return nil, status.Errorf(codes.InvalidArgument, "comparison_start_date cannot be after comparison_end_date")
+		}
+	}
+
+	current, err := s.scorePeriod(ctx, startTime, endTime, req.UseCurrentWeights)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to score current period", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to score current period")
+	}
+
+	previous, err := s.scorePeriod(ctx, comparisonStart, comparisonEnd, req.UseCurrentWeights)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to score comparison period", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to score comparison period")
+	}
+
+	response := &pb.CompareScoresResponse{
+		ComparisonStartDate: timestamppb.New(comparisonStart),
+		ComparisonEndDate:   timestamppb.New(comparisonEnd),
+		Overall:             scoreChange(0, "", current.overallScore(), previous.overallScore()),
+	}
+
+	seen := make(map[int64]bool)
+	for _, id := range append(current.order, previous.order...) {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		name, ok := current.names[id]
+		if !ok {
+			name = previous.names[id]
+		}
+		response.Categories = append(response.Categories,
+			scoreChange(id, name, current.categoryScore(id), previous.categoryScore(id)))
+	}
+
+	return response, nil
+}
+
+// previousPeriod returns the range of the same length that ends right before
+// start. Bounds are inclusive with second precision, as in the repository.
+func previousPeriod(start, end time.Time) (time.Time, time.Time) {
+	previousEnd := start.Add(-time.Second)
+	return previousEnd.Add(-end.Sub(start)), previousEnd
+}
+
+func (s *RatingsService) scorePeriod(ctx context.Context, start, end time.Time, useCurrentWeights bool) (*periodScores, error) {
+	repo := s.repoFor(ctx, useCurrentWeights)
+	overall, err := repo.GetOverallScore(start.Format(DATE_FORMAT), end.Format(DATE_FORMAT))
+	if err != nil {
+		return nil, err
+	}
+
+	ratings, err := repo.GetWeightedRatings(start.Format(DATE_FORMAT), end.Format(DATE_FORMAT))
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int64]ScoreSum)
+	result := &periodScores{
+		rated:      len(ratings) > 0,
+		overall:    overall,
+		categories: make(map[int64]int32),
+		names:      make(map[int64]string),
+	}
+	for _, rating := range ratings {
+		if _, ok := scores[rating.CategoryID]; !ok {
+			result.order = append(result.order, rating.CategoryID)
+			result.names[rating.CategoryID] = rating.Category
+		}
+		scores[rating.CategoryID] = addScore(scores[rating.CategoryID], rating)
+	}
+
+	for id, sum := range scores {
+		result.categories[id] = sum.score()
+	}
+
+	return result, nil
+}
+
+// scoreChange compares two scores, either of which is nil when its period
+// has no ratings. The changes are only set when both are.
+func scoreChange(categoryID int64, category string, current, previous *float32) *pb.ScoreChange {
+	change := &pb.ScoreChange{
+		CategoryId: categoryID,
+		Category:   category,
+		Current:    current,
+		Previous:   previous,
+	}
+	if current == nil || previous == nil {
+		return change
+	}
+	absolute := *current - *previous
+	change.AbsoluteChange = &absolute
+	if *previous != 0 {
+		relative := 100 * absolute / *previous
+		change.RelativeChange = &relative
+	}
+	return change
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/tracing"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+const NOT_AVAILABLE = "N/A"
+
+func (s *RatingsService) ExportAggregatedScores(ctx context.Context, req *pb.AggregatedScoresRequest) (*pb.ExportAggregatedScoresResponse, error) {
+	slog.InfoContext(ctx, "Processing ExportAggregatedScores request", "start_date", req.StartDate.AsTime(), "end_date", req.EndDate.AsTime())
+
+	report, err := s.rangeReport(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	_, span := otel.Tracer(TRACER_NAME).Start(ctx, "writeReportCSV")
+	err = writeReportCSV(&buf, report)
+	span.SetAttributes(attribute.Int("report.csv_bytes", buf.Len()))
+	tracing.End(span, err)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to write report", "granularity", report.granularity, "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to export report")
+	}
+
+	return &pb.ExportAggregatedScoresResponse{
+		Filename: reportFilename(report.opts),
+		Csv:      buf.Bytes(),
+	}, nil
+}
+
+// writeReportCSV pivots the report into the table of the README: a row per
+// category with its number of ratings, its score in every period of the range
+// and its score over the whole range. Periods without any ratings are missing
+// from the report, they still get a column.
+func writeReportCSV(w io.Writer, report *aggregatedReport) error {
+	g, err := newGranularity(report.granularity, report.opts.WeekStart)
+	if err != nil {
+		return err
+	}
+
+	var labels []string
+	columns := make(map[int64]int)
+	for t := g.truncate(report.opts.Start.In(report.opts.location())); !t.After(report.opts.End); t = g.next(t) {
+		columns[t.Unix()] = len(labels)
+		labels = append(labels, g.label(t))
+	}
+
+	cells := make(map[int64][]string, len(report.categories))
+	for _, category := range report.categories {
+		row := make([]string, len(labels))
+		for i := range row {
+			row[i] = NOT_AVAILABLE
+		}
+		cells[category.ID] = row
+	}
+
+	totals := make(map[int64]*pb.CategoryScore)
+	for _, score := range report.scores {
+		if score.Type == pb.ScoreEnum_RATINGS {
+			for _, category := range score.Categories {
+				totals[category.CategoryId] = category
+			}
+			continue
+		}
+
+		column, ok := columns[score.StartDate.AsTime().Unix()]
+		if !ok {
+			return fmt.Errorf("period %s is outside of the range", score.Value)
+		}
+		for _, category := range score.Categories {
+			if category.Ratings > 0 {
+				cells[category.CategoryId][column] = formatPercent(category.Score)
+			}
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	header := append(append([]string{"Category", "Ratings"}, labels...), "Score")
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, category := range report.categories {
+		ratings, score := int32(0), NOT_AVAILABLE
+		if total := totals[category.ID]; total != nil && total.Ratings > 0 {
+			ratings, score = total.Ratings, formatPercent(total.Score)
+		}
+
+		record := append(append([]string{escapeCSVCell(category.Name), strconv.Itoa(int(ratings))}, cells[category.ID]...), score)
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeCSVCell keeps spreadsheets from evaluating a category name as a
+// formula, by prefixing names that start like one with a quote.
+func escapeCSVCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func formatPercent(score int32) string {
+	return strconv.Itoa(int(score)) + "%"
+}
+
+func reportFilename(opts ReportOptions) string {
+	location := opts.location()
+	return fmt.Sprintf("scores_%s_%s.csv", opts.Start.In(location).Format("2006-01-02"), opts.End.In(location).Format("2006-01-02"))
+}
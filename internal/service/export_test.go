@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+func exportCSV(t *testing.T, ratingsService *RatingsService, req *pb.AggregatedScoresRequest) (*pb.ExportAggregatedScoresResponse, [][]string) {
+	t.Helper()
+
+	response, err := ratingsService.ExportAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(response.Csv)).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	return response, records
+}
+
+func TestExportAggregatedScores(t *testing.T) {
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	response, records := exportCSV(t, NewRatingsService(store), &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 1, 4, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_DAILY,
+	})
+
+	if response.Filename != "scores_2025-01-01_2025-01-04.csv" {
+		t.Fatalf("Unexpected filename %q", response.Filename)
+	}
+
+	// The retired Tone category is kept because it has ratings in the range,
+	// 2025-01-04 has no ratings at all.
+	expected := `Category,Ratings,2025-01-01,2025-01-02,2025-01-03,2025-01-04,Score
+Spelling,3,70%,80%,N/A,N/A,73%
+Grammar,2,60%,N/A,0%,N/A,30%
+GDPR,2,80%,100%,N/A,N/A,91%
+Tone,1,N/A,20%,N/A,N/A,20%
+`
+	if string(response.Csv) != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, response.Csv)
+	}
+	if len(records) != 5 {
+		t.Fatalf("Expected a header and 4 categories, got %d rows", len(records))
+	}
+}
+
+func TestExportAggregatedScoresEmptyRange(t *testing.T) {
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	_, records := exportCSV(t, NewRatingsService(store), &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 2, 2, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_DAILY,
+	})
+
+	expected := [][]string{
+		{"Category", "Ratings", "2025-02-01", "2025-02-02", "Score"},
+		{"Spelling", "0", "N/A", "N/A", "N/A"},
+		{"Grammar", "0", "N/A", "N/A", "N/A"},
+		{"GDPR", "0", "N/A", "N/A", "N/A"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, records)
+	}
+	for i := range expected {
+		if strings.Join(records[i], ",") != strings.Join(expected[i], ",") {
+			t.Fatalf("Unexpected row %d: %v, expected %v", i, records[i], expected[i])
+		}
+	}
+}
+
+func TestExportAggregatedScoresEscapesFormulas(t *testing.T) {
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+	if _, err := store.CreateCategory(`=HYPERLINK("http://example.com")`, 1); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	_, records := exportCSV(t, NewRatingsService(store), &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 2, 1, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_DAILY,
+	})
+	if name := records[len(records)-1][0]; name != `'=HYPERLINK("http://example.com")` {
+		t.Fatalf("Expected the formula to be escaped, got %q", name)
+	}
+
+	tests := map[string]string{
+		"+1":       "'+1",
+		"-1":       "'-1",
+		"@SUM(A1)": "'@SUM(A1)",
+		"\tTab":    "'\tTab",
+		"\rReturn": "'\rReturn",
+		"Grammar":  "Grammar",
+		"A=B":      "A=B",
+		"":         "",
+	}
+	for cell, expected := range tests {
+		if escaped := escapeCSVCell(cell); escaped != expected {
+			t.Fatalf("Expected %q for %q, got %q", expected, cell, escaped)
+		}
+	}
+}
+
+func TestExportAggregatedScoresMatchesReport(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+	req := &pb.AggregatedScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC)),
+		TimeZone:  "Europe/Tallinn",
+	}
+
+	report, err := ratingsService.GetAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, records := exportCSV(t, ratingsService, req)
+
+	header := records[0]
+	columns := make(map[string]int)
+	for i, label := range header {
+		columns[label] = i
+	}
+	rows := make(map[string][]string)
+	for _, record := range records[1:] {
+		rows[record[0]] = record
+	}
+
+	totals := report.Scores[0]
+	if len(rows) != len(totals.Categories) {
+		t.Fatalf("Expected %d categories, got %d", len(totals.Categories), len(rows))
+	}
+	for _, category := range totals.Categories {
+		row := rows[category.Name]
+		if row[1] != strconv.Itoa(int(category.Ratings)) || row[len(row)-1] != formatPercent(category.Score) {
+			t.Fatalf("Unexpected totals for %s: %v, expected %v", category.Name, row, category)
+		}
+	}
+
+	for _, score := range report.Scores[1:] {
+		column, ok := columns[score.Value]
+		if !ok {
+			t.Fatalf("Missing column for %s in %v", score.Value, header)
+		}
+		for _, category := range score.Categories {
+			expected := NOT_AVAILABLE
+			if category.Ratings > 0 {
+				expected = formatPercent(category.Score)
+			}
+			if got := rows[category.Name][column]; got != expected {
+				t.Fatalf("Unexpected %s score for %s: %s, expected %s", score.Value, category.Name, got, expected)
+			}
+		}
+	}
+}
+
+func TestExportAggregatedScoresInvalidRange(t *testing.T) {
+	store, err := database.NewMemoryStore(database.Fixture{})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	_, err = NewRatingsService(store).ExportAggregatedScores(context.Background(), &pb.AggregatedScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+}
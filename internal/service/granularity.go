@@ -0,0 +1,297 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+const MAX_BUCKETS = 1000
+
+// ReportOptions describe the requested range the report is built for.
+// Periods follow the wall clock in Location, UTC when it is nil. WeekStart
+// only affects weekly reports.
+type ReportOptions struct {
+	Start     time.Time
+	End       time.Time
+	WeekStart time.Weekday
+	Location  *time.Location
+}
+
+func (o ReportOptions) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
+
+type granularity struct {
+	scoreType pb.ScoreEnum
+	truncate  func(time.Time) time.Time
+	next      func(time.Time) time.Time
+	label     func(time.Time) string
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfHour keeps the instant's own offset, so the repeated hour at the end
+// of daylight saving time stays a separate bucket.
+func startOfHour(t time.Time) time.Time {
+	return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+}
+
+func newGranularity(requested pb.Granularity, weekStart time.Weekday) (granularity, error) {
+	switch requested {
+	case pb.Granularity_GRANULARITY_HOURLY:
+		return granularity{
+			scoreType: pb.ScoreEnum_HOURLY,
+			truncate:  startOfHour,
+			next:      func(t time.Time) time.Time { return t.Add(time.Hour) },
+			label:     func(t time.Time) string { return t.Format("2006-01-02T15:04Z07:00") },
+		}, nil
+	case pb.Granularity_GRANULARITY_DAILY:
+		return granularity{
+			scoreType: pb.ScoreEnum_DAILY,
+			truncate:  startOfDay,
+			next:      func(t time.Time) time.Time { return t.AddDate(0, 0, 1) },
+			label:     func(t time.Time) string { return t.Format("2006-01-02") },
+		}, nil
+	case pb.Granularity_GRANULARITY_WEEKLY:
+		return granularity{
+			scoreType: pb.ScoreEnum_WEEKLY,
+			truncate: func(t time.Time) time.Time {
+				day := startOfDay(t)
+				return day.AddDate(0, 0, -((int(day.Weekday()) - int(weekStart) + 7) % 7))
+			},
+			next:  func(t time.Time) time.Time { return t.AddDate(0, 0, 7) },
+			label: isoWeekLabel,
+		}, nil
+	case pb.Granularity_GRANULARITY_MONTHLY:
+		return granularity{
+			scoreType: pb.ScoreEnum_MONTHLY,
+			truncate: func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+			},
+			next:  func(t time.Time) time.Time { return t.AddDate(0, 1, 0) },
+			label: func(t time.Time) string { return t.Format("2006-01") },
+		}, nil
+	case pb.Granularity_GRANULARITY_QUARTERLY:
+		return granularity{
+			scoreType: pb.ScoreEnum_QUARTERLY,
+			truncate: func(t time.Time) time.Time {
+				return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, t.Location())
+			},
+			next:  func(t time.Time) time.Time { return t.AddDate(0, 3, 0) },
+			label: func(t time.Time) string { return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())+2)/3) },
+		}, nil
+	default:
+		return granularity{}, fmt.Errorf("unknown granularity: %v", requested)
+	}
+}
+
+// isoWeekLabel names a week after the ISO-8601 week of its Monday, so weeks
+// starting on Sunday get the number of the ISO week they mostly overlap.
+func isoWeekLabel(weekStart time.Time) string {
+	monday := weekStart.AddDate(0, 0, (int(time.Monday)-int(weekStart.Weekday())+7)%7)
+	year, week := monday.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+func ParseWeekStart(value string) (time.Weekday, error) {
+	switch strings.ToLower(value) {
+	case "monday":
+		return time.Monday, nil
+	case "sunday":
+		return time.Sunday, nil
+	default:
+		return 0, fmt.Errorf("unsupported week start: %q, expected monday or sunday", value)
+	}
+}
+
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+func resolveGranularity(requested pb.Granularity, start, end time.Time) pb.Granularity {
+	if requested != pb.Granularity_GRANULARITY_AUTO {
+		return requested
+	}
+	if withinMinMonth(start, end) || withinCalendarMonth(start, end) {
+		return pb.Granularity_GRANULARITY_DAILY
+	}
+	return pb.Granularity_GRANULARITY_WEEKLY
+}
+
+// countBuckets returns how many periods of the given granularity the range
+// touches, stopping as soon as MAX_BUCKETS is exceeded.
+func countBuckets(requested pb.Granularity, opts ReportOptions) (int, error) {
+	g, err := newGranularity(requested, opts.WeekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for t := g.truncate(opts.Start.In(opts.location())); !t.After(opts.End) && count <= MAX_BUCKETS; t = g.next(t) {
+		count++
+	}
+	return count, nil
+}
+
+func CalculateReport(ratings []database.Rating, categories []database.Category, requested pb.Granularity, opts ReportOptions) ([]*pb.Score, error) {
+	g, err := newGranularity(requested, opts.WeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return calculatePeriodReport(ratings, categories, g, opts), nil
+}
+
+// CalculateRollupReport calculates the report from daily rollups instead of
+// ratings. It is only exact for periods made of whole days in UTC, so the
+// location of opts must be UTC and the granularity must not be hourly.
+func CalculateRollupReport(scores []database.DailyScore, categories []database.Category, requested pb.Granularity, opts ReportOptions) ([]*pb.Score, error) {
+	if requested == pb.Granularity_GRANULARITY_HOURLY || opts.location() != time.UTC {
+		return nil, fmt.Errorf("daily rollups cannot be reported %v in %v", requested, opts.location())
+	}
+	g, err := newGranularity(requested, opts.WeekStart)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) == 0 {
+		return []*pb.Score{}, nil
+	}
+
+	var report []*pb.Score
+	builder := newReportBuilder(g, categories, opts)
+	for _, score := range scores {
+		day, err := time.Parse(database.DAY_FORMAT, score.Day)
+		if err != nil {
+			return nil, err
+		}
+		if period := builder.addDaily(day, score); period != nil {
+			report = append(report, period)
+		}
+	}
+	if period := builder.flush(); period != nil {
+		report = append(report, period)
+	}
+
+	return append([]*pb.Score{builder.totals()}, report...), nil
+}
+
+// calculatePeriodReport expects ratings ordered by creation time, as
+// returned by Repository.GetWeightedRatings.
+func calculatePeriodReport(ratings []database.Rating, categories []database.Category, g granularity, opts ReportOptions) []*pb.Score {
+	if len(ratings) == 0 {
+		return []*pb.Score{}
+	}
+
+	var report []*pb.Score
+	builder := newReportBuilder(g, categories, opts)
+	for _, rating := range ratings {
+		if score := builder.add(rating); score != nil {
+			report = append(report, score)
+		}
+	}
+	if score := builder.flush(); score != nil {
+		report = append(report, score)
+	}
+
+	return append([]*pb.Score{builder.totals()}, report...)
+}
+
+// reportBuilder turns ratings ordered by creation time into period scores. It
+// only keeps the sums of the current period and of the whole range, so
+// memory does not grow with the number of ratings.
+type reportBuilder struct {
+	g           granularity
+	opts        ReportOptions
+	categories  []database.Category
+	period      *pb.Score
+	periodStart time.Time
+	container   ScoreContainer[ScoreSum]
+	total       ScoreContainer[ScoreSum]
+}
+
+func newReportBuilder(g granularity, categories []database.Category, opts ReportOptions) *reportBuilder {
+	return &reportBuilder{
+		g:          g,
+		opts:       opts,
+		categories: categories,
+		container:  createEmptyContainer[ScoreSum](),
+		total:      createEmptyContainer[ScoreSum](),
+	}
+}
+
+// add returns the previous period when the rating starts a new one.
+func (b *reportBuilder) add(rating database.Rating) *pb.Score {
+	completed := b.enter(rating.CreatedAt)
+	b.container = scoreByCategory[ScoreSum](b.container, rating, addScore)
+	b.total = scoreByCategory[ScoreSum](b.total, rating, addScore)
+	return completed
+}
+
+// addDaily adds the ratings rolled up on day, like add.
+func (b *reportBuilder) addDaily(day time.Time, score database.DailyScore) *pb.Score {
+	completed := b.enter(day)
+	addDailyScore(b.container, score)
+	addDailyScore(b.total, score)
+	return completed
+}
+
+func addDailyScore(container ScoreContainer[ScoreSum], score database.DailyScore) {
+	sum := container[score.CategoryID]
+	sum.addGroup(score.Weight, score.Ratings, score.ValueSum)
+	container[score.CategoryID] = sum
+}
+
+// enter moves to the period of t and returns the previous period when t is
+// in a new one.
+func (b *reportBuilder) enter(t time.Time) *pb.Score {
+	start := b.g.truncate(t.In(b.opts.location()))
+	if b.period != nil && start.Equal(b.periodStart) {
+		return nil
+	}
+
+	completed := b.flush()
+	b.periodStart = start
+	b.period = preparePeriod(b.g, start, b.opts)
+	return completed
+}
+
+// flush returns the current period, or nil when there is none.
+func (b *reportBuilder) flush() *pb.Score {
+	if b.period == nil {
+		return nil
+	}
+
+	score := preparePeriodReport(b.period, b.categories, b.container)
+	b.period = nil
+	b.container = createEmptyContainer[ScoreSum]()
+	return score
+}
+
+// totals returns the RATINGS row for everything added so far.
+func (b *reportBuilder) totals() *pb.Score {
+	return prepareTotalReport(b.categories, b.total)
+}
+
+func preparePeriod(g granularity, start time.Time, opts ReportOptions) *pb.Score {
+	end := g.next(start).Add(-time.Second)
+	return &pb.Score{
+		Type:      g.scoreType,
+		Value:     g.label(start),
+		StartDate: timestamppb.New(start),
+		EndDate:   timestamppb.New(end),
+		Partial:   start.Before(opts.Start) || end.After(opts.End),
+	}
+}
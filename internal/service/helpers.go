@@ -2,66 +2,153 @@ package service
 
 import (
 	"math"
+	"slices"
+	"sort"
 	"time"
+
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
 )
 
-type ScoreType struct {
-	Value  int32
-	Weight float64
+// ScoreSum accumulates weighted ratings, so a score can be calculated without
+// keeping the ratings themselves. Values are summed as integers per weight
+// and only combined in score, so the score does not depend on the order or
+// grouping the ratings are added in: a daily rollup of ratings scores exactly
+// like the ratings themselves.
+//
+// Ratings are added in place, a copy of a sum shares its weights with the
+// original. Periods and the totals of a report each keep their own sums.
+type ScoreSum struct {
+	Ratings int32
+	weights []weightSum
 }
 
-type ScoreContainerValue interface {
-	int32 | []ScoreType
+// weightSum holds the ratings with the same weight, ordered by weight.
+type weightSum struct {
+	weight  float64
+	ratings int64
+	values  int64
 }
 
-type ScoreContainer[T ScoreContainerValue] struct {
-	Spelling   T
-	Grammar    T
-	Gdpr       T
-	Randomness T
+func (s *ScoreSum) add(value int32, weight float64) {
+	s.addGroup(weight, 1, int64(value))
 }
 
+// addGroup adds a number of ratings with the same weight and the sum of
+// their values.
+func (s *ScoreSum) addGroup(weight float64, ratings int32, values int64) {
+	s.Ratings += ratings
+	i := sort.Search(len(s.weights), func(i int) bool { return s.weights[i].weight >= weight })
+	if i < len(s.weights) && s.weights[i].weight == weight {
+		s.weights[i].ratings += int64(ratings)
+		s.weights[i].values += values
+		return
+	}
+
+	s.weights = slices.Insert(s.weights, i, weightSum{weight: weight, ratings: int64(ratings), values: values})
+}
+
+func (s ScoreSum) score() int32 {
+	var weightSum, valueSum float64
+	for _, w := range s.weights {
+		weightSum += w.weight * float64(w.ratings)
+		valueSum += w.weight * float64(w.values) / 5.0
+	}
+	if valueSum == 0 || weightSum == 0 {
+		return 0
+	}
+
+	return int32(math.Round(100 * (valueSum / weightSum)))
+}
+
+type ScoreContainerValue interface {
+	int32 | ScoreSum
+}
+
+// ScoreContainer holds values per rating category id.
+type ScoreContainer[T ScoreContainerValue] map[int64]T
+
 const MIN_MONTH_LENGTH = 28
 
 func createEmptyContainer[T ScoreContainerValue]() ScoreContainer[T] {
-	var zero T
-	return ScoreContainer[T]{
-		Spelling:   zero,
-		Grammar:    zero,
-		Gdpr:       zero,
-		Randomness: zero,
-	}
+	return make(ScoreContainer[T])
 }
 
-func withinCalendarMonth(start, end time.Time) bool {
-	return start.Year() == end.Year() && start.Month() == end.Month()
+func scoreByCategory[T ScoreContainerValue](container ScoreContainer[T], rating database.Rating, updateFunc func(T, database.Rating) T) ScoreContainer[T] {
+	container[rating.CategoryID] = updateFunc(container[rating.CategoryID], rating)
+	return container
 }
 
-func withinMinMonth(start, end time.Time) bool {
-	thirtyOneDaysAgo := end.AddDate(0, 0, -MIN_MONTH_LENGTH)
-	return start.After(thirtyOneDaysAgo)
+func addScore(s ScoreSum, r database.Rating) ScoreSum {
+	s.add(r.Value, r.Weight)
+	return s
 }
 
-func calculateAverage(nums []int32) int32 {
-	if len(nums) == 0 {
-		return 0
+// reportCategories drops retired categories that have no ratings among the
+// ones being reported.
+func reportCategories(categories []database.Category, ratings []database.Rating) []database.Category {
+	rated := make(map[int64]bool)
+	for _, rating := range ratings {
+		rated[rating.CategoryID] = true
 	}
-	var sum int32
-	for _, n := range nums {
-		sum += n
+
+	return filterRetiredCategories(categories, rated)
+}
+
+func filterRetiredCategories(categories []database.Category, rated map[int64]bool) []database.Category {
+	result := make([]database.Category, 0, len(categories))
+	for _, category := range categories {
+		if !category.Retired() || rated[category.ID] {
+			result = append(result, category)
+		}
 	}
-	return sum / int32(len(nums))
+	return result
 }
 
-func calculateWeightedScore(scores []ScoreType) int32 {
-	var weightSum, valueSum float64
-	for _, score := range scores {
-		weightSum += score.Weight
-		valueSum += (float64(score.Value) / 5.0) * score.Weight
+func prepareCategoryScores(categories []database.Category, container ScoreContainer[ScoreSum]) []*pb.CategoryScore {
+	scores := make([]*pb.CategoryScore, 0, len(categories))
+	for _, category := range categories {
+		scores = append(scores, &pb.CategoryScore{
+			CategoryId: category.ID,
+			Name:       category.Name,
+			Score:      container[category.ID].score(),
+			Ratings:    container[category.ID].Ratings,
+		})
 	}
-	if valueSum == 0 || weightSum == 0 {
-		return 0
+	return scores
+}
+
+// legacyCategoryFields picks the values for the fixed Spelling, Grammar, GDPR
+// and Randomness fields that old clients still read.
+func legacyCategoryFields(scores []*pb.CategoryScore, value func(*pb.CategoryScore) int32) (spelling, grammar, gdpr, randomness int32) {
+	for _, score := range scores {
+		switch score.Name {
+		case SPELLING:
+			spelling = value(score)
+		case GRAMMAR:
+			grammar = value(score)
+		case GDPR:
+			gdpr = value(score)
+		case RANDOMNESS:
+			randomness = value(score)
+		}
 	}
+	return spelling, grammar, gdpr, randomness
+}
 
-	return int32(math.Round(100 * (valueSum / weightSum)))
+func categoryScore(score *pb.CategoryScore) int32 {
+	return score.Score
+}
+
+func categoryRatings(score *pb.CategoryScore) int32 {
+	return score.Ratings
+}
+
+func withinCalendarMonth(start, end time.Time) bool {
+	return start.Year() == end.Year() && start.Month() == end.Month()
+}
+
+func withinMinMonth(start, end time.Time) bool {
+	thirtyOneDaysAgo := end.AddDate(0, 0, -MIN_MONTH_LENGTH)
+	return start.After(thirtyOneDaysAgo)
 }
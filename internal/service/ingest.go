@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+const (
+	MIN_RATING        = 0
+	MAX_RATING        = 5
+	SUBMIT_BATCH_SIZE = 500
+	// MAX_CLOCK_SKEW is how far in the future created_at may be, to allow
+	// for clients whose clocks run ahead.
+	MAX_CLOCK_SKEW = 5 * time.Minute
+)
+
+func (s *RatingsService) SubmitRating(ctx context.Context, req *pb.SubmitRatingRequest) (*pb.SubmitRatingResponse, error) {
+	slog.InfoContext(ctx, "Processing SubmitRating request", "ticket_id", req.TicketId)
+
+	categories, err := s.categoryIDs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+
+	rating, err := newRating(req, categories, time.Now())
+	if err != nil {
+		slog.WarnContext(ctx, "Invalid rating", "error", err)
+		return nil, err
+	}
+
+	id, err := s.store(ctx).InsertRating(rating)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to store rating", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to store rating")
+	}
+
+	return &pb.SubmitRatingResponse{Id: id}, nil
+}
+
+// SubmitRatings validates every rating on its own and stores the valid ones
+// in transactions of SUBMIT_BATCH_SIZE ratings once the client has closed the
+// stream. A failed transaction rejects only the ratings of its batch. When
+// the stream fails or is cancelled before that, nothing is stored, so the
+// client can resend the whole stream.
+func (s *RatingsService) SubmitRatings(stream pb.Service_SubmitRatingsServer) error {
+	ctx := stream.Context()
+	slog.InfoContext(ctx, "Processing SubmitRatings stream")
+
+	categories, err := s.categoryIDs(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+
+	response := &pb.SubmitRatingsResponse{Results: []*pb.SubmitRatingResult{}}
+	var ratings []database.Rating
+	var ratingResults []*pb.SubmitRatingResult
+
+	for index := int32(0); ; index++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to receive rating, none of the stream is stored", "received", index, "error", err)
+			return err
+		}
+
+		result := &pb.SubmitRatingResult{Index: index}
+		response.Results = append(response.Results, result)
+
+		rating, err := newRating(req, categories, time.Now())
+		if err != nil {
+			result.Code = int32(status.Code(err))
+			result.Message = status.Convert(err).Message()
+			response.Rejected++
+			continue
+		}
+
+		ratings = append(ratings, rating)
+		ratingResults = append(ratingResults, result)
+	}
+
+	for start := 0; start < len(ratings); start += SUBMIT_BATCH_SIZE {
+		end := min(start+SUBMIT_BATCH_SIZE, len(ratings))
+		ids, err := s.store(ctx).InsertRatings(ratings[start:end])
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to store ratings", "ratings", end-start, "error", err)
+		}
+		for i, result := range ratingResults[start:end] {
+			if err != nil {
+				result.Code = int32(codes.Internal)
+				result.Message = "failed to store rating"
+				response.Rejected++
+				continue
+			}
+			result.Id = ids[i]
+			response.Accepted++
+		}
+	}
+
+	slog.InfoContext(ctx, "SubmitRatings finished", "accepted", response.Accepted, "rejected", response.Rejected)
+	return stream.SendAndClose(response)
+}
+
+func (s *RatingsService) categoryIDs(ctx context.Context) (map[int64]bool, error) {
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int64]bool, len(categories))
+	for _, category := range categories {
+		if !category.Retired() {
+			ids[category.ID] = true
+		}
+	}
+	return ids, nil
+}
+
+func newRating(req *pb.SubmitRatingRequest, categories map[int64]bool, now time.Time) (database.Rating, error) {
+	if req.TicketId <= 0 || req.ReviewerId <= 0 || req.RevieweeId <= 0 {
+		return database.Rating{}, status.Errorf(codes.InvalidArgument, "ticket_id, reviewer_id and reviewee_id are required")
+	}
+
+	if req.Value < MIN_RATING || req.Value > MAX_RATING {
+		return database.Rating{}, status.Errorf(codes.InvalidArgument, "value must be between %d and %d, got %d", MIN_RATING, MAX_RATING, req.Value)
+	}
+
+	if !categories[req.CategoryId] {
+		return database.Rating{}, status.Errorf(codes.InvalidArgument, "unknown or retired category_id %d", req.CategoryId)
+	}
+
+	createdAt := now
+	if req.CreatedAt != nil {
+		if err := req.CreatedAt.CheckValid(); err != nil {
+			return database.Rating{}, status.Errorf(codes.InvalidArgument, "invalid created_at: %v", err)
+		}
+		createdAt = req.CreatedAt.AsTime()
+		if createdAt.After(now.Add(MAX_CLOCK_SKEW)) {
+			return database.Rating{}, status.Errorf(codes.InvalidArgument, "created_at %s is in the future", createdAt.Format(time.RFC3339))
+		}
+	}
+
+	return database.Rating{
+		TicketID:   req.TicketId,
+		CategoryID: req.CategoryId,
+		ReviewerID: req.ReviewerId,
+		RevieweeID: req.RevieweeId,
+		Value:      req.Value,
+		CreatedAt:  createdAt,
+	}, nil
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/testutil"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+func copyDatabase(t *testing.T) *database.Repository {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "database.db")
+	if err := copyFile(testDatabase, path); err != nil {
+		t.Fatalf("Failed to copy database: %v", err)
+	}
+
+	repo, err := database.NewRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func startServer(t *testing.T, ratingsService *RatingsService) pb.ServiceClient {
+	t.Helper()
+
+	conn := testutil.Serve(t, nil, func(server *grpc.Server) {
+		pb.RegisterServiceServer(server, ratingsService)
+	})
+	return pb.NewServiceClient(conn)
+}
+
+func TestSubmitRating(t *testing.T) {
+	ratingsService := NewRatingsService(copyDatabase(t))
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	response, err := ratingsService.SubmitRating(context.Background(), &pb.SubmitRatingRequest{
+		TicketId:   1,
+		CategoryId: 1,
+		ReviewerId: 1,
+		RevieweeId: 7,
+		Value:      5,
+		CreatedAt:  timestamppb.New(createdAt),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Id == 0 {
+		t.Fatal("Expected rating id")
+	}
+
+	score, err := ratingsService.GetOverallScore(context.Background(), &pb.OverallScoreRequest{
+		StartDate: timestamppb.New(createdAt.Add(-time.Hour)),
+		EndDate:   timestamppb.New(createdAt.Add(time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if score.OverallScore != 100 {
+		t.Fatalf("Expected stored rating to be scored, got %v", score.OverallScore)
+	}
+}
+
+func TestSubmitRatingValidation(t *testing.T) {
+	ratingsService := NewRatingsService(copyDatabase(t))
+
+	requests := []*pb.SubmitRatingRequest{
+		{TicketId: 1, CategoryId: 1, ReviewerId: 1, RevieweeId: 7, Value: 6},
+		{TicketId: 1, CategoryId: 1, ReviewerId: 1, RevieweeId: 7, Value: -1},
+		{TicketId: 1, CategoryId: 999, ReviewerId: 1, RevieweeId: 7, Value: 3},
+		{CategoryId: 1, ReviewerId: 1, RevieweeId: 7, Value: 3},
+		{TicketId: 1, CategoryId: 1, ReviewerId: 1, RevieweeId: 7, Value: 3, CreatedAt: &timestamppb.Timestamp{Seconds: 1, Nanos: 1e9}},
+		{TicketId: 1, CategoryId: 1, ReviewerId: 1, RevieweeId: 7, Value: 3, CreatedAt: &timestamppb.Timestamp{Seconds: 253402300800}},
+		{TicketId: 1, CategoryId: 1, ReviewerId: 1, RevieweeId: 7, Value: 3, CreatedAt: timestamppb.New(time.Now().Add(time.Hour))},
+	}
+
+	for _, req := range requests {
+		_, err := ratingsService.SubmitRating(context.Background(), req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument for %v, got %v", req, err)
+		}
+	}
+}
+
+func TestSubmitRatingsStream(t *testing.T) {
+	client := startServer(t, NewRatingsService(copyDatabase(t)))
+
+	stream, err := client.SubmitRatings(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+
+	total := SUBMIT_BATCH_SIZE + 10
+	for i := 0; i < total; i++ {
+		value := int32(i % 6)
+		if i == 3 {
+			value = 9
+		}
+		err := stream.Send(&pb.SubmitRatingRequest{TicketId: 1, CategoryId: 2, ReviewerId: 1, RevieweeId: 7, Value: value})
+		if err != nil {
+			t.Fatalf("Failed to send rating: %v", err)
+		}
+	}
+
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Results) != total || response.Accepted != int32(total-1) || response.Rejected != 1 {
+		t.Fatalf("Unexpected response: %d results, %d accepted, %d rejected", len(response.Results), response.Accepted, response.Rejected)
+	}
+
+	for i, result := range response.Results {
+		if result.Index != int32(i) {
+			t.Fatalf("Expected result %d in order, got index %d", i, result.Index)
+		}
+		if i == 3 && (result.Code != int32(codes.InvalidArgument) || result.Id != 0) {
+			t.Fatalf("Expected rejected rating, got %v", result)
+		}
+		if i != 3 && (result.Code != int32(codes.OK) || result.Id == 0) {
+			t.Fatalf("Expected stored rating, got %v", result)
+		}
+	}
+}
+
+// cancelledStream delivers ratings and then fails like a stream the client
+// cancelled.
+type cancelledStream struct {
+	grpc.ServerStream
+	ratings []*pb.SubmitRatingRequest
+}
+
+func (s *cancelledStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *cancelledStream) Recv() (*pb.SubmitRatingRequest, error) {
+	if len(s.ratings) == 0 {
+		return nil, status.Error(codes.Canceled, "context canceled")
+	}
+	req := s.ratings[0]
+	s.ratings = s.ratings[1:]
+	return req, nil
+}
+
+func (s *cancelledStream) SendAndClose(*pb.SubmitRatingsResponse) error {
+	return nil
+}
+
+func TestSubmitRatingsCancelled(t *testing.T) {
+	repo := copyDatabase(t)
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	total := SUBMIT_BATCH_SIZE + 10
+	stream := &cancelledStream{}
+	for i := 0; i < total; i++ {
+		stream.ratings = append(stream.ratings, &pb.SubmitRatingRequest{
+			TicketId: 1, CategoryId: 2, ReviewerId: 1, RevieweeId: 7, Value: 3, CreatedAt: timestamppb.New(createdAt),
+		})
+	}
+
+	err := NewRatingsService(repo).SubmitRatings(stream)
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("Expected Canceled, got %v", err)
+	}
+
+	ratings, err := repo.GetWeightedRatings("2026-03-01", "2026-03-02")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ratings) != 0 {
+		t.Fatalf("Expected no ratings of the cancelled stream to be stored, got %d", len(ratings))
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"helpdesk-ratings/internal/database"
+)
+
+// SOURCE_DATABASE is the sample database the tests read. It is never
+// written, the tests use a migrated copy of it.
+const SOURCE_DATABASE = "../../database.db"
+
+// testDatabase is the migrated copy of SOURCE_DATABASE, set by TestMain.
+var testDatabase string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "service-test")
+	if err != nil {
+		log.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	testDatabase = filepath.Join(dir, "database.db")
+	if err := copyFile(SOURCE_DATABASE, testDatabase); err != nil {
+		os.RemoveAll(dir)
+		log.Fatalf("The tests need the sample database at %s: %v", SOURCE_DATABASE, err)
+	}
+
+	migrator, err := database.NewMigrator(testDatabase)
+	if err != nil {
+		os.RemoveAll(dir)
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		migrator.Close()
+		os.RemoveAll(dir)
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+	migrator.Close()
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
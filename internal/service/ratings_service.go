@@ -2,20 +2,35 @@ package service
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"helpdesk-ratings/internal/database"
+	"helpdesk-ratings/internal/tracing"
 	pb "helpdesk-ratings/proto/gen"
 )
 
 type RatingsService struct {
 	pb.UnimplementedServiceServer
-	repo *database.Repository
+	repo      database.Store
+	weekStart time.Weekday
 }
 
+type Option func(*RatingsService)
+
+func WithWeekStart(day time.Weekday) Option {
+	return func(s *RatingsService) {
+		s.weekStart = day
+	}
+}
+
+const TRACER_NAME = "helpdesk-ratings/internal/service"
+
 const (
 	DATE_FORMAT = "2006-01-02T15:04:05"
 	SPELLING    = "Spelling"
@@ -24,23 +39,51 @@ const (
 	RANDOMNESS  = "Randomness"
 )
 
-func NewRatingsService(repo *database.Repository) *RatingsService {
-	return &RatingsService{repo: repo}
+// store returns the repository for a request, its queries are traced as part
+// of the request.
+func (s *RatingsService) store(ctx context.Context) database.Store {
+	return database.NewTracedStore(ctx, s.repo)
+}
+
+// repoFor returns the repository that scores ratings with the weights the
+// request asks for.
+func (s *RatingsService) repoFor(ctx context.Context, useCurrentWeights bool) database.Store {
+	if useCurrentWeights {
+		return s.store(ctx).WithCurrentWeights()
+	}
+	return s.store(ctx)
+}
+
+func NewRatingsService(repo database.Store, opts ...Option) *RatingsService {
+	s := &RatingsService{repo: repo, weekStart: time.Monday}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *RatingsService) GetOverallScore(ctx context.Context, req *pb.OverallScoreRequest) (*pb.OverallScoreResponse, error) {
 	startTime := req.StartDate.AsTime()
 	endTime := req.EndDate.AsTime()
-	log.Printf("Processing GetOverallScore request: %v to %v", startTime, endTime)
+	slog.InfoContext(ctx, "Processing GetOverallScore request", "start_date", startTime, "end_date", endTime)
 
 	if req.StartDate == nil || req.EndDate == nil || startTime.After(endTime) {
-		log.Printf("Invalid date range: %v to %v", startTime, endTime)
+		slog.WarnContext(ctx, "Invalid date range", "start_date", startTime, "end_date", endTime)
 		return nil, status.Errorf(codes.InvalidArgument, "start_date and end_date are required, and start_date cannot be after end_date")
 	}
 
-	overallScore, err := s.repo.GetOverallScore(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT))
+	if req.TimeZone != "" {
+		location, err := LoadTimeZone(req.TimeZone)
+		if err != nil {
+			slog.WarnContext(ctx, "Invalid time zone", "time_zone", req.TimeZone)
+			return nil, status.Errorf(codes.InvalidArgument, "unknown time_zone %q", req.TimeZone)
+		}
+		startTime, endTime = localDays(startTime, endTime, location)
+	}
+
+	overallScore, err := s.repoFor(ctx, req.UseCurrentWeights).GetOverallScore(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT))
 	if err != nil {
-		log.Printf("Failed to get overall score: %v", err)
+		slog.ErrorContext(ctx, "Failed to get overall score", "error", err)
 		return nil, status.Errorf(codes.Internal, "failed to retrieve overall score")
 	}
 
@@ -49,182 +92,228 @@ func (s *RatingsService) GetOverallScore(ctx context.Context, req *pb.OverallSco
 	}, nil
 }
 
-func (s *RatingsService) GetAggregatedScores(ctx context.Context, req *pb.AggregatedScoresRequest) (*pb.AggregatedScoresResponse, error) {
-	startTime := req.StartDate.AsTime()
-	endTime := req.EndDate.AsTime()
-	log.Printf("Processing GetAggregatedScores request: %v to %v", startTime, endTime)
+// localDays widens the range to the whole local days in location that it
+// touches, as UTC times the repository can be queried with.
+func localDays(start, end time.Time, location *time.Location) (time.Time, time.Time) {
+	start = start.In(location)
+	end = end.In(location)
+	firstDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+	nextDay := time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, location)
+	return firstDay.UTC(), nextDay.Add(-time.Second).UTC()
+}
 
-	if req.StartDate == nil || req.EndDate == nil || startTime.After(endTime) {
-		log.Printf("Invalid date range: %v to %v", startTime, endTime)
-		return nil, status.Errorf(codes.InvalidArgument, "start_date and end_date are required, and start_date cannot be after end_date")
-	}
+func (s *RatingsService) GetAggregatedScores(ctx context.Context, req *pb.AggregatedScoresRequest) (*pb.AggregatedScoresResponse, error) {
+	slog.InfoContext(ctx, "Processing GetAggregatedScores request", "start_date", req.StartDate.AsTime(), "end_date", req.EndDate.AsTime())
 
-	ratings, err := s.repo.GetWeightedRatings(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT))
+	report, err := s.rangeReport(ctx, req, 0)
 	if err != nil {
-		log.Printf("Failed to get ratings: %v", err)
-		return nil, status.Errorf(codes.Internal, "Failed to retrieve ratings")
-	}
-
-	var report []*pb.Score
-
-	if withinMinMonth(startTime, endTime) || withinCalendarMonth(startTime, endTime) {
-		log.Printf("Generating daily report: %v to %v", startTime, endTime)
-		dailyReport, err := CalculateDailyReport(ratings)
-
-		if err != nil {
-			log.Printf("Failed to calculate daily report: %v", err)
-			return nil, status.Errorf(codes.Internal, "Failed to calculate daily report")
-		}
-		report = append(report, dailyReport...)
-	} else {
-		log.Printf("Generating weekly report: %v to %v", startTime, endTime)
-		weeklyReport, err := CalculateWeeklyReport(ratings)
-		if err != nil {
-			log.Printf("Failed to calculate weekly report: %v", err)
-			return nil, status.Errorf(codes.Internal, "Failed to calculate weekly report")
-		}
-		report = append(report, weeklyReport...)
+		return nil, err
 	}
 
 	return &pb.AggregatedScoresResponse{
-		Scores: report,
+		Scores: report.scores,
 	}, nil
 }
 
-func CalculateDailyReport(ratings []database.Rating) ([]*pb.Score, error) {
-	if len(ratings) == 0 {
-		return []*pb.Score{}, nil
-	}
+// aggregatedReport is a report together with the granularity, options and
+// categories it was built with.
+type aggregatedReport struct {
+	granularity pb.Granularity
+	opts        ReportOptions
+	categories  []database.Category
+	scores      []*pb.Score
+}
 
-	var report []*pb.Score
-	container := createEmptyContainer[[]ScoreType]()
-	totalContainer := createEmptyContainer[int32]()
+// traceReport describes the requested report on the span of the RPC.
+func traceReport(ctx context.Context, granularity pb.Granularity, opts ReportOptions) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("report.granularity", granularity.String()),
+		attribute.String("report.start_date", opts.Start.Format(time.RFC3339)),
+		attribute.String("report.end_date", opts.End.Format(time.RFC3339)),
+		attribute.String("report.time_zone", opts.location().String()))
+}
+
+// rangeReport builds the report over all ratings of the range, or over the
+// ratings of agentID unless it is 0. Periods made of whole UTC days are
+// calculated from the daily rollups, hourly periods and periods in other time
+// zones do not line up with them and are calculated from the ratings. The
+// rollups are not kept per agent, so agent reports always use the ratings.
+func (s *RatingsService) rangeReport(ctx context.Context, req *pb.AggregatedScoresRequest, agentID int64) (*aggregatedReport, error) {
+	granularity, opts, err := s.aggregationOptions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := limitBuckets(ctx, granularity, opts); err != nil {
+		return nil, err
+	}
 
-	score := &pb.Score{
-		Type:  pb.ScoreEnum_DAILY,
-		Value: ratings[0].Day,
+	traceReport(ctx, granularity, opts)
+	repo := s.repoFor(ctx, req.UseCurrentWeights)
+	if agentID != 0 {
+		return s.ratingsReport(ctx, granularity, opts, func(startDate, endDate string) ([]database.Rating, error) {
+			return repo.GetAgentWeightedRatings(agentID, startDate, endDate)
+		})
+	}
+	if granularity == pb.Granularity_GRANULARITY_HOURLY || opts.location() != time.UTC {
+		return s.ratingsReport(ctx, granularity, opts, repo.GetWeightedRatings)
 	}
+	return s.rollupReport(ctx, granularity, opts, repo.GetDailyScores)
+}
 
-	for _, rating := range ratings {
-		var err error
-		if rating.Day == score.Value {
-			container, err = scoreByCategory[[]ScoreType](container, rating, func(s []ScoreType, r database.Rating) []ScoreType {
-				return append(s, ScoreType{Value: r.Value, Weight: r.Weight})
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to score rating: %w", err)
-			}
-		} else {
-			report, totalContainer = preparePeriodReport(report, score, container, totalContainer)
+func (s *RatingsService) ratingsReport(ctx context.Context, granularity pb.Granularity, opts ReportOptions, getRatings func(startDate, endDate string) ([]database.Rating, error)) (*aggregatedReport, error) {
+	startTime, endTime := opts.Start, opts.End
 
-			score.Value = rating.Day
-			container = createEmptyContainer[[]ScoreType]()
+	ratings, err := getRatings(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get ratings", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve ratings")
+	}
 
-			container, err = scoreByCategory[[]ScoreType](container, rating, func(s []ScoreType, r database.Rating) []ScoreType {
-				return append(s, ScoreType{Value: r.Value, Weight: r.Weight})
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to score rating: %w", err)
-			}
-		}
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve categories")
 	}
 
-	report, totalContainer = preparePeriodReport(report, score, container, totalContainer)
+	slog.InfoContext(ctx, "Generating report", "granularity", granularity, "start_date", startTime, "end_date", endTime)
+	categories = reportCategories(categories, ratings)
+	_, span := otel.Tracer(TRACER_NAME).Start(ctx, "CalculateReport", trace.WithAttributes(
+		attribute.Int("report.ratings", len(ratings)),
+		attribute.Int("report.categories", len(categories))))
+	report, err := CalculateReport(ratings, categories, granularity, opts)
+	span.SetAttributes(attribute.Int("report.scores", len(report)))
+	tracing.End(span, err)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to calculate report", "granularity", granularity, "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to calculate report")
+	}
 
-	return append(prepareTotalReport(totalContainer), report...), nil
+	return &aggregatedReport{
+		granularity: granularity,
+		opts:        opts,
+		categories:  categories,
+		scores:      report,
+	}, nil
 }
 
-func CalculateWeeklyReport(ratings []database.Rating) ([]*pb.Score, error) {
-	if len(ratings) == 0 {
-		return []*pb.Score{}, nil
+func (s *RatingsService) rollupReport(ctx context.Context, granularity pb.Granularity, opts ReportOptions, getScores func(startDate, endDate string) ([]database.DailyScore, error)) (*aggregatedReport, error) {
+	startTime, endTime := opts.Start, opts.End
+
+	scores, err := getScores(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get daily scores", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve ratings")
 	}
 
-	var report []*pb.Score
-	currentDay := ratings[0].Day
-	dayCounter, weekNumber := int32(1), int32(1)
-	container := createEmptyContainer[[]ScoreType]()
-	totalContainer := createEmptyContainer[int32]()
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
 
-	score := &pb.Score{
-		Type: pb.ScoreEnum_WEEKLY,
+	slog.InfoContext(ctx, "Generating report from daily rollups", "granularity", granularity, "start_date", startTime, "end_date", endTime)
+	rated := make(map[int64]bool)
+	for _, score := range scores {
+		rated[score.CategoryID] = true
+	}
+	categories = filterRetiredCategories(categories, rated)
+	_, span := otel.Tracer(TRACER_NAME).Start(ctx, "CalculateRollupReport", trace.WithAttributes(
+		attribute.Int("report.daily_scores", len(scores)),
+		attribute.Int("report.categories", len(categories))))
+	report, err := CalculateRollupReport(scores, categories, granularity, opts)
+	span.SetAttributes(attribute.Int("report.scores", len(report)))
+	tracing.End(span, err)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to calculate report", "granularity", granularity, "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to calculate report")
 	}
 
-	for _, rating := range ratings {
-		var err error
-		container, err = scoreByCategory[[]ScoreType](container, rating, func(v []ScoreType, r database.Rating) []ScoreType {
-			return append(v, ScoreType{Value: r.Value, Weight: r.Weight})
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to score rating: %w", err)
-		}
+	return &aggregatedReport{
+		granularity: granularity,
+		opts:        opts,
+		categories:  categories,
+		scores:      report,
+	}, nil
+}
 
-		if rating.Day != currentDay {
-			dayCounter++
-		}
+// aggregationOptions validates the request and resolves the granularity and
+// report options shared by the unary and the streaming report.
+func (s *RatingsService) aggregationOptions(ctx context.Context, req *pb.AggregatedScoresRequest) (pb.Granularity, ReportOptions, error) {
+	startTime := req.StartDate.AsTime()
+	endTime := req.EndDate.AsTime()
 
-		if dayCounter > 7 {
-			dayCounter = 1
-			weekNumber++
-			score.Value = fmt.Sprintf("Week %d", weekNumber-1)
-			report, totalContainer = preparePeriodReport(report, score, container, totalContainer)
-			container = createEmptyContainer[[]ScoreType]()
-		}
+	if req.StartDate == nil || req.EndDate == nil || startTime.After(endTime) {
+		slog.WarnContext(ctx, "Invalid date range", "start_date", startTime, "end_date", endTime)
+		return 0, ReportOptions{}, status.Errorf(codes.InvalidArgument, "start_date and end_date are required, and start_date cannot be after end_date")
+	}
 
-		currentDay = rating.Day
+	location, err := LoadTimeZone(req.TimeZone)
+	if err != nil {
+		slog.WarnContext(ctx, "Invalid time zone", "time_zone", req.TimeZone)
+		return 0, ReportOptions{}, status.Errorf(codes.InvalidArgument, "unknown time_zone %q", req.TimeZone)
 	}
 
-	if dayCounter > 1 {
-		report, totalContainer = preparePeriodReport(report, score, container, totalContainer)
+	granularity := resolveGranularity(req.Granularity, startTime.In(location), endTime.In(location))
+	if _, err := newGranularity(granularity, s.weekStart); err != nil {
+		slog.WarnContext(ctx, "Invalid granularity", "granularity", req.Granularity)
+		return 0, ReportOptions{}, status.Errorf(codes.InvalidArgument, "unknown granularity")
 	}
 
-	return append(prepareTotalReport(totalContainer), report...), nil
+	return granularity, ReportOptions{Start: startTime, End: endTime, WeekStart: s.weekStart, Location: location}, nil
 }
 
-func preparePeriodReport(report []*pb.Score, score *pb.Score, container ScoreContainer[[]ScoreType], totalContainer ScoreContainer[int32]) ([]*pb.Score, ScoreContainer[int32]) {
-	if score == nil || len(container.Spelling) == 0 {
-		return report, ScoreContainer[int32]{}
+// limitBuckets rejects ranges with more than MAX_BUCKETS periods. Only the
+// unary reports are limited, they hold every period in memory while the
+// stream sends them one at a time.
+func limitBuckets(ctx context.Context, granularity pb.Granularity, opts ReportOptions) error {
+	buckets, err := countBuckets(granularity, opts)
+	if err != nil {
+		slog.WarnContext(ctx, "Invalid granularity", "granularity", granularity)
+		return status.Errorf(codes.InvalidArgument, "unknown granularity")
 	}
+	if buckets > MAX_BUCKETS {
+		slog.WarnContext(ctx, "Too many buckets", "granularity", granularity, "start_date", opts.Start, "end_date", opts.End)
+		return status.Errorf(codes.InvalidArgument, "the range produces more than %d buckets, choose a coarser granularity", MAX_BUCKETS)
+	}
+	return nil
+}
 
-	return append(report, &pb.Score{
-			Type:       score.Type,
-			Value:      score.Value,
-			Spelling:   calculateWeightedScore(container.Spelling),
-			Grammar:    calculateWeightedScore(container.Grammar),
-			Gdpr:       calculateWeightedScore(container.Gdpr),
-			Randomness: calculateWeightedScore(container.Randomness),
-		}), ScoreContainer[int32]{
-			Spelling:   totalContainer.Spelling + int32(len(container.Spelling)),
-			Grammar:    totalContainer.Grammar + int32(len(container.Grammar)),
-			Gdpr:       totalContainer.Gdpr + int32(len(container.Gdpr)),
-			Randomness: totalContainer.Randomness + int32(len(container.Randomness)),
-		}
+func CalculateDailyReport(ratings []database.Rating, categories []database.Category, opts ReportOptions) ([]*pb.Score, error) {
+	return CalculateReport(ratings, categories, pb.Granularity_GRANULARITY_DAILY, opts)
+}
+
+func CalculateWeeklyReport(ratings []database.Rating, categories []database.Category, opts ReportOptions) ([]*pb.Score, error) {
+	return CalculateReport(ratings, categories, pb.Granularity_GRANULARITY_WEEKLY, opts)
 }
 
-func scoreByCategory[T ScoreContainerValue](container ScoreContainer[T], rating database.Rating, updateFunc func(T, database.Rating) T) (ScoreContainer[T], error) {
-	switch rating.Category {
-	case SPELLING:
-		container.Spelling = updateFunc(container.Spelling, rating)
-	case GRAMMAR:
-		container.Grammar = updateFunc(container.Grammar, rating)
-	case GDPR:
-		container.Gdpr = updateFunc(container.Gdpr, rating)
-	case RANDOMNESS:
-		container.Randomness = updateFunc(container.Randomness, rating)
-	default:
-		log.Printf("unknown category: %s", rating.Category)
-		return container, fmt.Errorf("unknown category: %s", rating.Category)
+func preparePeriodReport(score *pb.Score, categories []database.Category, container ScoreContainer[ScoreSum]) *pb.Score {
+	categoryScores := prepareCategoryScores(categories, container)
+	spelling, grammar, gdpr, randomness := legacyCategoryFields(categoryScores, categoryScore)
+
+	return &pb.Score{
+		Type:       score.Type,
+		Value:      score.Value,
+		Spelling:   spelling,
+		Grammar:    grammar,
+		Gdpr:       gdpr,
+		Randomness: randomness,
+		Categories: categoryScores,
+		StartDate:  score.StartDate,
+		EndDate:    score.EndDate,
+		Partial:    score.Partial,
 	}
-	return container, nil
 }
 
-func prepareTotalReport(container ScoreContainer[int32]) []*pb.Score {
-	var total []*pb.Score
+func prepareTotalReport(categories []database.Category, container ScoreContainer[ScoreSum]) *pb.Score {
+	categoryScores := prepareCategoryScores(categories, container)
+	spelling, grammar, gdpr, randomness := legacyCategoryFields(categoryScores, categoryRatings)
 
-	return append(total, &pb.Score{
+	return &pb.Score{
 		Type:       pb.ScoreEnum_RATINGS,
-		Spelling:   container.Spelling,
-		Grammar:    container.Grammar,
-		Gdpr:       container.Gdpr,
-		Randomness: container.Randomness,
-	})
+		Spelling:   spelling,
+		Grammar:    grammar,
+		Gdpr:       gdpr,
+		Randomness: randomness,
+		Categories: categoryScores,
+	}
 }
@@ -2,15 +2,24 @@ package service
 
 import (
 	"context"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"helpdesk-ratings/internal/database"
 	pb "helpdesk-ratings/proto/gen"
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestGetOverallScore(t *testing.T) {
-	repo, err := database.NewRepository("../../database.db")
+	repo, err := database.NewRepository(testDatabase)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -40,7 +49,7 @@ func TestGetOverallScore(t *testing.T) {
 }
 
 func TestGetAggregatedScoresDaily(t *testing.T) {
-	repo, err := database.NewRepository("../../database.db")
+	repo, err := database.NewRepository(testDatabase)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -73,7 +82,7 @@ func TestGetAggregatedScoresDaily(t *testing.T) {
 }
 
 func TestGetAggregatedScoresWeekly(t *testing.T) {
-	repo, err := database.NewRepository("../../database.db")
+	repo, err := database.NewRepository(testDatabase)
 	if err != nil {
 		t.Fatalf("Failed to create repository: %v", err)
 	}
@@ -102,15 +111,846 @@ func TestGetAggregatedScoresWeekly(t *testing.T) {
 }
 
 func TestCalculateWeightedScore(t *testing.T) {
-	scores := []ScoreType{
-		{Value: 4, Weight: 0.7},
-		{Value: 5, Weight: 0.3},
+	expected := int32(86)
+	var first, second ScoreSum
+	first.add(4, 0.7)
+	first.add(5, 0.3)
+	// The order ratings are added in must not change the score.
+	second.add(5, 0.3)
+	second.add(4, 0.7)
+
+	for _, sum := range []ScoreSum{first, second} {
+		if result := sum.score(); result != expected || sum.Ratings != 2 {
+			t.Fatalf("Expected %v from 2 ratings, got %v from %v", expected, result, sum.Ratings)
+		}
 	}
+}
 
-	expected := int32(86)
-	result := calculateWeightedScore(scores)
+func TestScoreSumAddInPlace(t *testing.T) {
+	var sum ScoreSum
+	sum.add(5, 0.7)
+	sum.add(4, 0.3)
+
+	// Weights the sum already holds are added to without allocating.
+	allocs := testing.AllocsPerRun(100, func() {
+		sum.add(3, 0.7)
+		sum.add(2, 0.3)
+	})
+	if allocs != 0 {
+		t.Fatalf("Expected no allocations, got %v per run", allocs)
+	}
+	if sum.Ratings != 2+2*101 {
+		t.Fatalf("Expected %d ratings, got %d", 2+2*101, sum.Ratings)
+	}
+}
+
+func TestGetTicketScoresPagination(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+
+	req := &pb.TicketScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 7, 23, 59, 59, 0, time.UTC)),
+		PageSize:  10,
+	}
+
+	first, err := ratingsService.GetTicketScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(first.Tickets) != 10 {
+		t.Fatalf("Expected 10 tickets, got %d", len(first.Tickets))
+	}
+
+	if first.NextPageToken == "" {
+		t.Fatal("Expected next page token")
+	}
+
+	req.PageToken = first.NextPageToken
+	second, err := ratingsService.GetTicketScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(second.Tickets) == 0 || second.Tickets[0].TicketId <= first.Tickets[len(first.Tickets)-1].TicketId {
+		t.Fatal("Expected second page to continue after the first one")
+	}
+
+	// The token only continues the request it was issued for.
+	for _, other := range []*pb.TicketScoresRequest{
+		{StartDate: req.StartDate, EndDate: timestamppb.New(time.Date(2025, 1, 8, 23, 59, 59, 0, time.UTC)), PageSize: 10, PageToken: first.NextPageToken},
+		{StartDate: timestamppb.New(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)), EndDate: req.EndDate, PageSize: 10, PageToken: first.NextPageToken},
+		{StartDate: req.StartDate, EndDate: req.EndDate, PageSize: 20, PageToken: first.NextPageToken},
+		{StartDate: req.StartDate, EndDate: req.EndDate, PageSize: 10, PageToken: strconv.FormatInt(first.Tickets[9].TicketId, 10)},
+		{StartDate: req.StartDate, EndDate: req.EndDate, PageSize: 10, PageToken: "not a token"},
+	} {
+		if _, err := ratingsService.GetTicketScores(context.Background(), other); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument for %v, got %v", other, err)
+		}
+	}
+
+	for _, ticket := range append(first.Tickets, second.Tickets...) {
+		if ticket.Score < 0 || ticket.Score > 100 {
+			t.Fatalf("Expected ticket score between 0 and 100, got %v", ticket.Score)
+		}
+	}
+}
+
+func TestCalculateTicketScores(t *testing.T) {
+	categories := []database.Category{
+		{ID: 1, Name: SPELLING, Weight: 0.7},
+		{ID: 2, Name: GRAMMAR, Weight: 0.3},
+		{ID: 3, Name: GDPR, Weight: 1},
+	}
+	ratings := []database.Rating{
+		{TicketID: 1, CategoryID: 1, Category: SPELLING, Value: 4, Weight: 0.7},
+		{TicketID: 1, CategoryID: 2, Category: GRAMMAR, Value: 5, Weight: 0.3},
+		{TicketID: 2, CategoryID: 3, Category: GDPR, Value: 5, Weight: 1},
+	}
+
+	tickets := CalculateTicketScores(ratings, categories)
+
+	if len(tickets) != 2 {
+		t.Fatalf("Expected 2 tickets, got %d", len(tickets))
+	}
+
+	if tickets[0].Score != 86 || tickets[0].Categories[0].Score != 80 || tickets[0].Categories[1].Score != 100 {
+		t.Fatalf("Unexpected scores for ticket 1: %v", tickets[0])
+	}
+
+	if tickets[0].Spelling != 80 || tickets[0].Grammar != 100 {
+		t.Fatalf("Expected legacy category fields to be filled: %v", tickets[0])
+	}
+
+	if tickets[1].TicketId != 2 || tickets[1].Score != 100 {
+		t.Fatalf("Unexpected scores for ticket 2: %v", tickets[1])
+	}
+}
+
+func TestCompareScores(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+
+	req := &pb.CompareScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 14, 23, 59, 59, 0, time.UTC)),
+	}
+
+	response, err := ratingsService.CompareScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedEnd := time.Date(2025, 1, 7, 23, 59, 59, 0, time.UTC)
+	if !response.ComparisonStartDate.AsTime().Equal(expectedStart) || !response.ComparisonEndDate.AsTime().Equal(expectedEnd) {
+		t.Fatalf("Expected comparison range %v - %v, got %v - %v", expectedStart, expectedEnd,
+			response.ComparisonStartDate.AsTime(), response.ComparisonEndDate.AsTime())
+	}
+
+	current, err := ratingsService.GetOverallScore(context.Background(), &pb.OverallScoreRequest{StartDate: req.StartDate, EndDate: req.EndDate})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Overall.GetCurrent() != current.OverallScore {
+		t.Fatalf("Expected current score %v, got %v", current.OverallScore, response.Overall.GetCurrent())
+	}
+
+	if response.Overall.AbsoluteChange == nil || response.Overall.GetAbsoluteChange() != response.Overall.GetCurrent()-response.Overall.GetPrevious() {
+		t.Fatalf("Unexpected absolute change %v", response.Overall.AbsoluteChange)
+	}
+
+	if len(response.Categories) == 0 {
+		t.Fatal("Expected category changes")
+	}
+	for _, change := range response.Categories {
+		if change.CategoryId == 0 || change.Category == "" {
+			t.Fatalf("Expected the category id and name, got %v", change)
+		}
+	}
+}
+
+// TestCompareScoresMissingPeriod compares periods in which not every
+// category, or nothing at all, was rated.
+func TestCompareScoresMissingPeriod(t *testing.T) {
+	store, err := database.NewMemoryStore(database.Fixture{
+		Categories: []database.Category{{ID: 1, Name: "Spelling", Weight: 1}, {ID: 2, Name: "Grammar", Weight: 1}},
+		Ratings: []database.Rating{
+			{ID: 1, TicketID: 1, CategoryID: 1, Value: 4, CreatedAt: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)},
+			{ID: 2, TicketID: 2, CategoryID: 1, Value: 5, CreatedAt: time.Date(2025, 1, 9, 9, 0, 0, 0, time.UTC)},
+			{ID: 3, TicketID: 2, CategoryID: 2, Value: 5, CreatedAt: time.Date(2025, 1, 9, 9, 0, 0, 0, time.UTC)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	ratingsService := NewRatingsService(store)
+
+	response, err := ratingsService.CompareScores(context.Background(), &pb.CompareScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 14, 23, 59, 59, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(response.Categories) != 2 {
+		t.Fatalf("Expected 2 categories, got %v", response.Categories)
+	}
+	spelling, grammar := response.Categories[0], response.Categories[1]
+	if spelling.CategoryId != 1 || spelling.GetCurrent() != 100 || spelling.GetPrevious() != 80 || spelling.GetAbsoluteChange() != 20 {
+		t.Fatalf("Unexpected Spelling change: %v", spelling)
+	}
+	if grammar.CategoryId != 2 || grammar.GetCurrent() != 100 || grammar.Previous != nil || grammar.AbsoluteChange != nil || grammar.RelativeChange != nil {
+		t.Fatalf("Expected Grammar without a previous score, got %v", grammar)
+	}
+
+	// Nothing was rated in the week before the first rating.
+	response, err = ratingsService.CompareScores(context.Background(), &pb.CompareScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 7, 23, 59, 59, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Overall.Current == nil || response.Overall.Previous != nil || response.Overall.AbsoluteChange != nil {
+		t.Fatalf("Expected an overall score without a previous one, got %v", response.Overall)
+	}
+	if len(response.Categories) != 1 || response.Categories[0].Previous != nil {
+		t.Fatalf("Expected Spelling without a previous score, got %v", response.Categories)
+	}
+}
+
+func TestScoreChange(t *testing.T) {
+	score := func(value float32) *float32 { return &value }
+
+	change := scoreChange(2, "Grammar", score(90), score(80))
+	if change.GetAbsoluteChange() != 10 || change.RelativeChange == nil || *change.RelativeChange != 12.5 {
+		t.Fatalf("Unexpected change: %v", change)
+	}
+
+	if scoreChange(2, "Grammar", score(90), score(0)).RelativeChange != nil {
+		t.Fatal("Expected no relative change when previous score is 0")
+	}
+
+	change = scoreChange(2, "Grammar", score(90), nil)
+	if change.GetCurrent() != 90 || change.Previous != nil || change.AbsoluteChange != nil || change.RelativeChange != nil {
+		t.Fatalf("Expected no change without a previous score, got %v", change)
+	}
+}
+
+func TestCalculateDailyReportUnknownCategory(t *testing.T) {
+	categories := []database.Category{
+		{ID: 1, Name: SPELLING, Weight: 1},
+		{ID: 5, Name: "Tone", Weight: 0.5},
+	}
+	ratings := []database.Rating{
+		{CreatedAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), CategoryID: 1, Category: SPELLING, Value: 5, Weight: 1},
+		{CreatedAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), CategoryID: 5, Category: "Tone", Value: 3, Weight: 0.5},
+		{CreatedAt: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), CategoryID: 5, Category: "Tone", Value: 4, Weight: 0.5},
+	}
+
+	report, err := CalculateDailyReport(ratings, categories, ReportOptions{
+		Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 1, 2, 23, 59, 59, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(report) != 3 {
+		t.Fatalf("Expected 3 scores, got %d", len(report))
+	}
+
+	total := report[0]
+	if total.Type != pb.ScoreEnum_RATINGS || total.Spelling != 1 || total.Categories[1].Name != "Tone" || total.Categories[1].Ratings != 2 {
+		t.Fatalf("Unexpected ratings row: %v", total)
+	}
+
+	if report[2].Categories[0].Ratings != 0 || report[2].Categories[1].Score != 80 {
+		t.Fatalf("Unexpected daily row: %v", report[2])
+	}
+}
+
+func TestGetAggregatedScoresMonthly(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+
+	req := &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_MONTHLY,
+	}
+
+	response, err := ratingsService.GetAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Scores) != 13 {
+		t.Fatalf("Expected 13 scores, got %d", len(response.Scores))
+	}
+
+	if response.Scores[1].Type != pb.ScoreEnum_MONTHLY || response.Scores[1].Value != "2025-01" {
+		t.Fatalf("Unexpected first month: %v", response.Scores[1])
+	}
+}
+
+func TestGetAggregatedScoresTooManyBuckets(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+
+	req := &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_HOURLY,
+	}
+
+	_, err = ratingsService.GetAggregatedScores(context.Background(), req)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestCalculateReportQuarterly(t *testing.T) {
+	categories := []database.Category{{ID: 1, Name: SPELLING, Weight: 1}}
+	ratings := []database.Rating{
+		{CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), CategoryID: 1, Value: 5, Weight: 1},
+		{CreatedAt: time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC), CategoryID: 1, Value: 3, Weight: 1},
+		{CreatedAt: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), CategoryID: 1, Value: 0, Weight: 1},
+	}
+
+	report, err := CalculateReport(ratings, categories, pb.Granularity_GRANULARITY_QUARTERLY, ReportOptions{
+		Start: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 6, 30, 23, 59, 59, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(report) != 3 || report[1].Value != "2025-Q1" || report[2].Value != "2025-Q2" {
+		t.Fatalf("Unexpected quarterly report: %v", report)
+	}
 
-	if result != expected {
-		t.Fatalf("Expected %v, got %v", expected, result)
+	if report[1].Categories[0].Score != 80 || report[1].Categories[0].Ratings != 2 {
+		t.Fatalf("Unexpected Q1 score: %v", report[1])
+	}
+}
+
+func TestGetAggregatedScoresIsoWeeks(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	req := &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 2, 1, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_WEEKLY,
+	}
+
+	response, err := NewRatingsService(repo).GetAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	weeks := response.Scores[1:]
+	if len(weeks) != 5 {
+		t.Fatalf("Expected 5 weeks, got %d", len(weeks))
+	}
+
+	first, last := weeks[0], weeks[len(weeks)-1]
+	if first.Value != "2025-W01" || !first.Partial || !first.StartDate.AsTime().Equal(time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Unexpected first week: %v", first)
+	}
+
+	if last.Value != "2025-W05" || !last.Partial || !last.EndDate.AsTime().Equal(time.Date(2025, 2, 2, 23, 59, 59, 0, time.UTC)) {
+		t.Fatalf("Unexpected last week: %v", last)
+	}
+
+	if weeks[1].Partial {
+		t.Fatalf("Expected full second week: %v", weeks[1])
+	}
+
+	response, err = NewRatingsService(repo, WithWeekStart(time.Sunday)).GetAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	last = response.Scores[len(response.Scores)-1]
+	if last.Value != "2025-W05" || last.Partial || last.StartDate.AsTime().Weekday() != time.Sunday {
+		t.Fatalf("Unexpected last Sunday week: %v", last)
+	}
+}
+
+func TestParseWeekStart(t *testing.T) {
+	if day, err := ParseWeekStart("Sunday"); err != nil || day != time.Sunday {
+		t.Fatalf("Expected Sunday, got %v, %v", day, err)
+	}
+
+	if _, err := ParseWeekStart("friday"); err == nil {
+		t.Fatal("Expected error for unsupported week start")
+	}
+}
+
+func TestCalculateReportTimeZone(t *testing.T) {
+	location, err := LoadTimeZone("America/New_York")
+	if err != nil {
+		t.Fatalf("Failed to load time zone: %v", err)
+	}
+
+	categories := []database.Category{{ID: 1, Name: SPELLING, Weight: 1}}
+	ratings := []database.Rating{
+		{CreatedAt: time.Date(2025, 11, 2, 3, 0, 0, 0, time.UTC), CategoryID: 1, Value: 5, Weight: 1},
+		{CreatedAt: time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), CategoryID: 1, Value: 5, Weight: 1},
+		{CreatedAt: time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC), CategoryID: 1, Value: 0, Weight: 1},
+	}
+	opts := ReportOptions{
+		Start:    time.Date(2025, 11, 2, 4, 0, 0, 0, time.UTC),
+		End:      time.Date(2025, 11, 3, 4, 59, 59, 0, time.UTC),
+		Location: location,
+	}
+
+	daily, err := CalculateDailyReport(ratings, categories, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(daily) != 3 || daily[1].Value != "2025-11-01" || daily[2].Value != "2025-11-02" {
+		t.Fatalf("Expected local days, got %v", daily)
+	}
+
+	if hours := daily[2].EndDate.AsTime().Sub(daily[2].StartDate.AsTime()); hours != 25*time.Hour-time.Second {
+		t.Fatalf("Expected a 25 hour day, got %v", hours)
+	}
+
+	if daily[2].Partial {
+		t.Fatalf("Expected full local day: %v", daily[2])
+	}
+
+	hourly, err := CalculateReport(ratings, categories, pb.Granularity_GRANULARITY_HOURLY, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(hourly) != 4 || hourly[2].Value != "2025-11-02T01:00-04:00" || hourly[3].Value != "2025-11-02T01:00-05:00" {
+		t.Fatalf("Expected repeated DST hour in separate buckets, got %v", hourly)
+	}
+}
+
+func TestGetAggregatedScoresInvalidTimeZone(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	req := &pb.AggregatedScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 7, 23, 59, 59, 0, time.UTC)),
+		TimeZone:  "Mars/Olympus_Mons",
+	}
+
+	_, err = NewRatingsService(repo).GetAggregatedScores(context.Background(), req)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestGetOverallScoreTimeZone(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+	score := func(start, end time.Time, timeZone string) float32 {
+		t.Helper()
+		response, err := ratingsService.GetOverallScore(context.Background(), &pb.OverallScoreRequest{
+			StartDate: timestamppb.New(start),
+			EndDate:   timestamppb.New(end),
+			TimeZone:  timeZone,
+		})
+		if err != nil {
+			t.Fatalf("Expected no error for %q, got %v", timeZone, err)
+		}
+		return response.OverallScore
+	}
+
+	// Noon UTC on 2025-01-03 is on 2025-01-04 in Auckland (UTC+13), the
+	// range covers that whole local day.
+	noon := time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC)
+	auckland := score(noon, noon, "Pacific/Auckland")
+	expected := score(time.Date(2025, 1, 3, 11, 0, 0, 0, time.UTC), time.Date(2025, 1, 4, 10, 59, 59, 0, time.UTC), "")
+	if auckland != expected {
+		t.Fatalf("Expected %v for the local day in Pacific/Auckland, got %v", expected, auckland)
+	}
+
+	utc := score(noon, noon, "UTC")
+	expected = score(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 3, 23, 59, 59, 0, time.UTC), "")
+	if utc != expected {
+		t.Fatalf("Expected %v for the UTC day, got %v", expected, utc)
+	}
+	if utc == auckland {
+		t.Fatalf("Expected the local days to score differently, got %v for both", utc)
+	}
+
+	_, err = ratingsService.GetOverallScore(context.Background(), &pb.OverallScoreRequest{
+		StartDate: timestamppb.New(noon),
+		EndDate:   timestamppb.New(noon),
+		TimeZone:  "Mars/Olympus_Mons",
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestGetAgentScores(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+
+	req := &pb.AgentScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 7, 23, 59, 59, 0, time.UTC)),
+	}
+
+	response, err := ratingsService.GetAgentScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Agents) == 0 {
+		t.Fatal("Expected agent scores")
+	}
+
+	for i, agent := range response.Agents {
+		if agent.Ratings == 0 || agent.Rank < 1 {
+			t.Fatalf("Unexpected agent score: %v", agent)
+		}
+		if i > 0 && agent.Score > response.Agents[i-1].Score {
+			t.Fatalf("Expected agents ranked best to worst, got %v after %v", agent, response.Agents[i-1])
+		}
+	}
+
+	best := response.Agents[0]
+	aggregated, err := ratingsService.GetAgentAggregatedScores(context.Background(), &pb.AgentAggregatedScoresRequest{
+		AgentId:     best.AgentId,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		Granularity: pb.Granularity_GRANULARITY_WEEKLY,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ratings int32
+	for _, category := range aggregated.Scores[0].Categories {
+		ratings += category.Ratings
+	}
+	if ratings != best.Ratings {
+		t.Fatalf("Expected %d ratings for agent %d, got %d", best.Ratings, best.AgentId, ratings)
+	}
+}
+
+func TestCalculateAgentScoresRanking(t *testing.T) {
+	categories := []database.Category{{ID: 1, Name: SPELLING, Weight: 1}}
+	ratings := []database.Rating{
+		{RevieweeID: 1, Reviewee: "Ann", CategoryID: 1, Value: 3, Weight: 1},
+		{RevieweeID: 2, Reviewee: "Bob", CategoryID: 1, Value: 5, Weight: 1},
+		{RevieweeID: 3, Reviewee: "Cid", CategoryID: 1, Value: 5, Weight: 1},
+		{RevieweeID: 3, Reviewee: "Cid", CategoryID: 1, Value: 5, Weight: 1},
+	}
+
+	agents := CalculateAgentScores(ratings, categories)
+
+	if len(agents) != 3 || agents[0].AgentId != 3 || agents[1].AgentId != 2 || agents[2].AgentId != 1 {
+		t.Fatalf("Unexpected ranking: %v", agents)
+	}
+
+	if agents[0].Rank != 1 || agents[1].Rank != 1 || agents[2].Rank != 3 {
+		t.Fatalf("Unexpected ranks: %v", agents)
+	}
+
+	if agents[2].Name != "Ann" || agents[2].Score != 60 {
+		t.Fatalf("Unexpected agent: %v", agents[2])
+	}
+}
+
+func TestGetReviewerCalibration(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	req := &pb.ReviewerCalibrationRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC)),
+	}
+
+	response, err := NewRatingsService(repo).GetReviewerCalibration(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Population) == 0 || len(response.Reviewers) == 0 {
+		t.Fatal("Expected population and reviewer statistics")
+	}
+
+	for _, reviewer := range response.Reviewers {
+		if reviewer.Ratings == 0 || reviewer.MeanRating < 0 || reviewer.MeanRating > 5 {
+			t.Fatalf("Unexpected reviewer statistics: %v", reviewer)
+		}
+	}
+}
+
+func TestCalculateReviewerCalibrationOutlier(t *testing.T) {
+	categories := []database.Category{{ID: 1, Name: SPELLING, Weight: 1}, {ID: 2, Name: GRAMMAR, Weight: 1}}
+
+	var ratings []database.Rating
+	for reviewer := int64(1); reviewer <= 6; reviewer++ {
+		value := int32(4)
+		if reviewer == 6 {
+			value = 1
+		}
+		ratings = append(ratings,
+			database.Rating{ReviewerID: reviewer, CategoryID: 1, Value: value},
+			database.Rating{ReviewerID: reviewer, CategoryID: 2, Value: value + 1},
+		)
+	}
+
+	population, reviewers := CalculateReviewerCalibration(ratings, categories, DEFAULT_OUTLIER_THRESHOLD)
+
+	if len(population) != 2 || population[0].MeanRating != 3.5 || population[1].MeanRating != 4.5 {
+		t.Fatalf("Unexpected population: %v", population)
+	}
+
+	if len(reviewers) != 6 {
+		t.Fatalf("Expected 6 reviewers, got %d", len(reviewers))
+	}
+
+	harsh := reviewers[5]
+	if !harsh.Outlier || harsh.Deviation != -2.5 || harsh.Categories[0].Deviation != -2.5 {
+		t.Fatalf("Expected harsh reviewer to be an outlier: %v", harsh)
+	}
+
+	for _, reviewer := range reviewers[:5] {
+		if reviewer.Outlier {
+			t.Fatalf("Unexpected outlier: %v", reviewer)
+		}
+	}
+}
+
+func TestCalculateReviewerCalibrationSampleStdDev(t *testing.T) {
+	categories := []database.Category{{ID: 1, Name: SPELLING, Weight: 1}}
+
+	// With one of n reviewers apart, its z-score is (n-1)/sqrt(n) with the
+	// sample standard deviation, and sqrt(n-1) with the population one.
+	for _, test := range []struct {
+		reviewers int64
+		outlier   bool
+	}{
+		{5, false},
+		{6, true},
+	} {
+		var ratings []database.Rating
+		for reviewer := int64(1); reviewer <= test.reviewers; reviewer++ {
+			value := int32(5)
+			if reviewer == test.reviewers {
+				value = 1
+			}
+			ratings = append(ratings, database.Rating{ReviewerID: reviewer, CategoryID: 1, Value: value})
+		}
+
+		_, reviewers := CalculateReviewerCalibration(ratings, categories, DEFAULT_OUTLIER_THRESHOLD)
+
+		n := float64(test.reviewers)
+		harsh := reviewers[len(reviewers)-1]
+		if math.Abs(harsh.ZScore+(n-1)/math.Sqrt(n)) > 1e-9 || harsh.Outlier != test.outlier {
+			t.Fatalf("Expected z-score %.4f and outlier %t among %d reviewers, got %.4f and %t", -(n-1)/math.Sqrt(n), test.outlier, test.reviewers, harsh.ZScore, harsh.Outlier)
+		}
+	}
+
+	_, reviewers := CalculateReviewerCalibration([]database.Rating{{ReviewerID: 1, CategoryID: 1, Value: 5}}, categories, DEFAULT_OUTLIER_THRESHOLD)
+	if reviewers[0].ZScore != 0 || reviewers[0].Outlier {
+		t.Fatalf("Expected no z-score for a single reviewer, got %v", reviewers[0])
+	}
+}
+
+func TestGetAggregatedScoresMemoryStore(t *testing.T) {
+	store, err := database.NewMemoryStore(database.Fixture{
+		Categories: []database.Category{{ID: 1, Name: SPELLING, Weight: 1}, {ID: 2, Name: GRAMMAR, Weight: 0.5}},
+		Ratings: []database.Rating{
+			{TicketID: 1, CategoryID: 1, ReviewerID: 1, RevieweeID: 2, Value: 5, CreatedAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)},
+			{TicketID: 1, CategoryID: 2, ReviewerID: 1, RevieweeID: 2, Value: 2, CreatedAt: time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)},
+			{TicketID: 2, CategoryID: 1, ReviewerID: 1, RevieweeID: 2, Value: 3, CreatedAt: time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	ratingsService := NewRatingsService(store)
+
+	response, err := ratingsService.GetAggregatedScores(context.Background(), &pb.AggregatedScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 7, 23, 59, 59, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Scores) != 3 {
+		t.Fatalf("Expected 3 scores, got %d", len(response.Scores))
+	}
+	if response.Scores[0].Spelling != 2 || response.Scores[0].Grammar != 1 {
+		t.Fatalf("Unexpected totals: %v", response.Scores[0])
+	}
+	if response.Scores[1].Value != "2025-01-01" || response.Scores[1].Spelling != 100 || response.Scores[1].Grammar != 40 {
+		t.Fatalf("Unexpected first day: %v", response.Scores[1])
+	}
+}
+
+// TestRollupReportMatchesRatings builds every report both from the daily
+// rollups and from the ratings, the two have to agree exactly.
+func TestRollupReportMatchesRatings(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ranges := [][2]time.Time{
+		{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{time.Date(2025, 2, 3, 13, 30, 0, 0, time.UTC), time.Date(2025, 5, 17, 8, 15, 0, 0, time.UTC)},
+		{time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)},
+	}
+	granularities := []pb.Granularity{
+		pb.Granularity_GRANULARITY_DAILY,
+		pb.Granularity_GRANULARITY_WEEKLY,
+		pb.Granularity_GRANULARITY_MONTHLY,
+		pb.Granularity_GRANULARITY_QUARTERLY,
+	}
+	categories, err := repo.GetCategories()
+	if err != nil {
+		t.Fatalf("Failed to get categories: %v", err)
+	}
+
+	for _, store := range []database.Store{repo, repo.WithCurrentWeights()} {
+		for _, r := range ranges {
+			start, end := r[0].Format(DATE_FORMAT), r[1].Format(DATE_FORMAT)
+			ratings, err := store.GetWeightedRatings(start, end)
+			if err != nil {
+				t.Fatalf("Failed to get ratings: %v", err)
+			}
+			scores, err := store.GetDailyScores(start, end)
+			if err != nil {
+				t.Fatalf("Failed to get daily scores: %v", err)
+			}
+
+			for _, granularity := range granularities {
+				opts := ReportOptions{Start: r[0], End: r[1], WeekStart: time.Monday}
+				expected, err := CalculateReport(ratings, reportCategories(categories, ratings), granularity, opts)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				report, err := CalculateRollupReport(scores, reportCategories(categories, ratings), granularity, opts)
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+
+				if len(report) != len(expected) {
+					t.Fatalf("Expected %d %v scores for %v, got %d", len(expected), granularity, r, len(report))
+				}
+				for i := range expected {
+					if !proto.Equal(report[i], expected[i]) {
+						t.Fatalf("Expected %v for %v, got %v", expected[i], r, report[i])
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := CalculateRollupReport(nil, categories, pb.Granularity_GRANULARITY_HOURLY, ReportOptions{}); err == nil {
+		t.Fatalf("Expected an error for an hourly rollup report")
+	}
+}
+
+func TestGetAggregatedScoresSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	store, err := database.LoadMemoryStore("../database/testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("Failed to load fixture: %v", err)
+	}
+
+	ctx, rpc := provider.Tracer("test").Start(context.Background(), "GetAggregatedScores")
+	_, err = NewRatingsService(store).GetAggregatedScores(ctx, &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 1, 3, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_DAILY,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rpc.End()
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		if span.Parent().SpanID() == rpc.SpanContext().SpanID() {
+			names = append(names, span.Name())
+		}
+		if span.Name() == "CalculateRollupReport" {
+			for _, kv := range span.Attributes() {
+				if kv.Key == "report.daily_scores" && kv.Value.AsInt64() != 7 {
+					t.Fatalf("Expected 7 daily scores, got %v", kv.Value)
+				}
+			}
+		}
+		if span.Name() == "GetAggregatedScores" {
+			found := false
+			for _, kv := range span.Attributes() {
+				found = found || kv.Key == "report.granularity" && kv.Value.AsString() == "GRANULARITY_DAILY"
+			}
+			if !found {
+				t.Fatalf("Expected the granularity on the RPC span, got %v", span.Attributes())
+			}
+		}
+	}
+	expected := []string{"GetDailyScores", "GetCategories", "CalculateRollupReport"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Fatalf("Expected child spans %v, got %v", expected, names)
 	}
 }
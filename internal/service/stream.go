@@ -0,0 +1,84 @@
+package service
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+// StreamAggregatedScores sends the same scores as GetAggregatedScores, but
+// reads ratings row by row and sends every period as soon as the next one
+// starts. The RATINGS totals are sent last, so they are sent even for a range
+// without ratings. Unlike the unary report, the range is not limited to
+// MAX_BUCKETS periods.
+func (s *RatingsService) StreamAggregatedScores(req *pb.AggregatedScoresRequest, stream pb.Service_StreamAggregatedScoresServer) error {
+	ctx := stream.Context()
+	slog.InfoContext(ctx, "Processing StreamAggregatedScores request", "start_date", req.StartDate.AsTime(), "end_date", req.EndDate.AsTime())
+
+	granularity, opts, err := s.aggregationOptions(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	g, err := newGranularity(granularity, opts.WeekStart)
+	if err != nil {
+		slog.WarnContext(ctx, "Invalid granularity", "granularity", granularity)
+		return status.Errorf(codes.InvalidArgument, "unknown granularity")
+	}
+
+	startDate, endDate := opts.Start.Format(DATE_FORMAT), opts.End.Format(DATE_FORMAT)
+	traceReport(ctx, granularity, opts)
+
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+
+	// Periods are sent before all ratings are read, so retired categories are
+	// filtered up front to keep the same columns in every period.
+	ratedIDs, err := s.store(ctx).GetRatedCategoryIDs(startDate, endDate)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get rated categories", "error", err)
+		return status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+	rated := make(map[int64]bool, len(ratedIDs))
+	for _, id := range ratedIDs {
+		rated[id] = true
+	}
+
+	slog.InfoContext(ctx, "Streaming report", "granularity", granularity, "start_date", opts.Start, "end_date", opts.End)
+	builder := newReportBuilder(g, filterRetiredCategories(categories, rated), opts)
+
+	var sendErr error
+	err = s.repoFor(ctx, req.UseCurrentWeights).StreamWeightedRatings(ctx, startDate, endDate, func(rating database.Rating) error {
+		if score := builder.add(rating); score != nil {
+			sendErr = stream.Send(score)
+		}
+		return sendErr
+	})
+	if sendErr != nil {
+		slog.ErrorContext(ctx, "Failed to send report", "granularity", granularity, "error", sendErr)
+		return sendErr
+	}
+	if ctx.Err() != nil {
+		slog.InfoContext(ctx, "StreamAggregatedScores cancelled", "error", ctx.Err())
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to stream ratings", "error", err)
+		return status.Errorf(codes.Internal, "Failed to retrieve ratings")
+	}
+
+	if score := builder.flush(); score != nil {
+		if err := stream.Send(score); err != nil {
+			slog.ErrorContext(ctx, "Failed to send report", "granularity", granularity, "error", err)
+			return err
+		}
+	}
+
+	return stream.Send(builder.totals())
+}
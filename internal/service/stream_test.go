@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+func TestStreamAggregatedScoresMatchesUnary(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+	client := startServer(t, ratingsService)
+
+	req := &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_WEEKLY,
+		TimeZone:    "Europe/Tallinn",
+	}
+
+	response, err := ratingsService.GetAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stream, err := client.StreamAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+
+	var streamed []*pb.Score
+	for {
+		score, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to receive score: %v", err)
+		}
+		streamed = append(streamed, score)
+	}
+
+	if len(streamed) != len(response.Scores) {
+		t.Fatalf("Expected %d scores, got %d", len(response.Scores), len(streamed))
+	}
+
+	// The unary response starts with the totals, the stream ends with them.
+	if !proto.Equal(streamed[len(streamed)-1], response.Scores[0]) {
+		t.Fatalf("Unexpected totals: %v, expected %v", streamed[len(streamed)-1], response.Scores[0])
+	}
+	for i, score := range streamed[:len(streamed)-1] {
+		if !proto.Equal(score, response.Scores[i+1]) {
+			t.Fatalf("Unexpected score %d: %v, expected %v", i, score, response.Scores[i+1])
+		}
+	}
+}
+
+func TestStreamAggregatedScoresEmptyRange(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	client := startServer(t, NewRatingsService(repo))
+
+	stream, err := client.StreamAggregatedScores(context.Background(), &pb.AggregatedScoresRequest{
+		StartDate: timestamppb.New(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2030, 1, 31, 23, 59, 59, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+
+	score, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected the totals, got %v", err)
+	}
+	if score.Type != pb.ScoreEnum_RATINGS || score.Spelling != 0 {
+		t.Fatalf("Unexpected totals: %v", score)
+	}
+
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Expected the stream to end, got %v", err)
+	}
+}
+
+func TestStreamAggregatedScoresInvalidRange(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	client := startServer(t, NewRatingsService(repo))
+
+	stream, err := client.StreamAggregatedScores(context.Background(), &pb.AggregatedScoresRequest{
+		StartDate: timestamppb.New(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)),
+		EndDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestStreamAggregatedScoresManyBuckets(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ratingsService := NewRatingsService(repo)
+	client := startServer(t, ratingsService)
+
+	req := &pb.AggregatedScoresRequest{
+		StartDate:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:     timestamppb.New(time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC)),
+		Granularity: pb.Granularity_GRANULARITY_HOURLY,
+	}
+
+	if _, err := ratingsService.GetAggregatedScores(context.Background(), req); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument from the unary report, got %v", err)
+	}
+
+	stream, err := client.StreamAggregatedScores(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+
+	periods := 0
+	for {
+		score, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to receive score: %v", err)
+		}
+		if score.Type == pb.ScoreEnum_HOURLY {
+			periods++
+		}
+	}
+
+	if periods <= MAX_BUCKETS {
+		t.Fatalf("Expected more than %d periods, got %d", MAX_BUCKETS, periods)
+	}
+}
+
+func TestStreamWeightedRatingsCancelled(t *testing.T) {
+	repo, err := database.NewRepository(testDatabase)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	read := 0
+	err = repo.StreamWeightedRatings(ctx, "2025-01-01T00:00:00", "2025-12-31T23:59:59", func(database.Rating) error {
+		read++
+		if read == 10 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if read >= 8*365*4 {
+		t.Fatalf("Expected the query to stop early, read %d ratings", read)
+	}
+}
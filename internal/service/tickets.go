@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"helpdesk-ratings/internal/database"
+	pb "helpdesk-ratings/proto/gen"
+)
+
+const (
+	DEFAULT_TICKET_PAGE_SIZE = 100
+	MAX_TICKET_PAGE_SIZE     = 1000
+)
+
+func (s *RatingsService) GetTicketScores(ctx context.Context, req *pb.TicketScoresRequest) (*pb.TicketScoresResponse, error) {
+	startTime := req.StartDate.AsTime()
+	endTime := req.EndDate.AsTime()
+	slog.InfoContext(ctx, "Processing GetTicketScores request", "start_date", startTime, "end_date", endTime, "page_token", req.PageToken)
+
+	if req.StartDate == nil || req.EndDate == nil || startTime.After(endTime) {
+		slog.WarnContext(ctx, "Invalid date range", "start_date", startTime, "end_date", endTime)
+		return nil, status.Errorf(codes.InvalidArgument, "start_date and end_date are required, and start_date cannot be after end_date")
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size cannot be negative")
+	}
+	if pageSize == 0 {
+		pageSize = DEFAULT_TICKET_PAGE_SIZE
+	}
+	if pageSize > MAX_TICKET_PAGE_SIZE {
+		pageSize = MAX_TICKET_PAGE_SIZE
+	}
+
+	page := ticketPageToken{Start: startTime.UnixNano(), End: endTime.UnixNano(), PageSize: pageSize}
+	if req.PageToken != "" {
+		token, err := decodeTicketPageToken(req.PageToken)
+		if err != nil {
+			slog.WarnContext(ctx, "Invalid page token", "page_token", req.PageToken)
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token")
+		}
+		if token.Start != page.Start || token.End != page.End || token.PageSize != page.PageSize {
+			slog.WarnContext(ctx, "Page token of another request", "page_token", req.PageToken)
+			return nil, status.Errorf(codes.InvalidArgument, "page_token was issued for another start_date, end_date or page_size")
+		}
+		page.AfterTicketID = token.AfterTicketID
+	}
+
+	// One extra ticket is requested to find out whether another page exists.
+	ratings, err := s.repoFor(ctx, req.UseCurrentWeights).GetTicketRatings(startTime.Format(DATE_FORMAT), endTime.Format(DATE_FORMAT), page.AfterTicketID, pageSize+1)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get ticket ratings", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve ticket ratings")
+	}
+
+	categories, err := s.store(ctx).GetCategories()
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get categories", "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve categories")
+	}
+
+	tickets := CalculateTicketScores(ratings, reportCategories(categories, ratings))
+
+	response := &pb.TicketScoresResponse{Tickets: tickets}
+	if len(tickets) > pageSize {
+		response.Tickets = tickets[:pageSize]
+		page.AfterTicketID = tickets[pageSize-1].TicketId
+		response.NextPageToken = page.encode()
+	}
+
+	return response, nil
+}
+
+// ticketPageToken is where the next page of GetTicketScores starts, together
+// with the range and page size of the request it was issued for. Clients get
+// it as opaque base64 encoded JSON.
+type ticketPageToken struct {
+	Start         int64 `json:"start"`
+	End           int64 `json:"end"`
+	PageSize      int   `json:"page_size"`
+	AfterTicketID int64 `json:"after_ticket_id"`
+}
+
+func (t ticketPageToken) encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeTicketPageToken(token string) (ticketPageToken, error) {
+	var t ticketPageToken
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// CalculateTicketScores expects ratings ordered by ticket id, as returned by
+// Repository.GetTicketRatings.
+func CalculateTicketScores(ratings []database.Rating, categories []database.Category) []*pb.TicketScore {
+	tickets := []*pb.TicketScore{}
+	if len(ratings) == 0 {
+		return tickets
+	}
+
+	ticketID := ratings[0].TicketID
+	var all ScoreSum
+	container := createEmptyContainer[ScoreSum]()
+
+	for _, rating := range ratings {
+		if rating.TicketID != ticketID {
+			tickets = append(tickets, prepareTicketScore(ticketID, categories, all, container))
+			ticketID = rating.TicketID
+			all = ScoreSum{}
+			container = createEmptyContainer[ScoreSum]()
+		}
+
+		container = scoreByCategory[ScoreSum](container, rating, addScore)
+		all = addScore(all, rating)
+	}
+
+	return append(tickets, prepareTicketScore(ticketID, categories, all, container))
+}
+
+func prepareTicketScore(ticketID int64, categories []database.Category, all ScoreSum, container ScoreContainer[ScoreSum]) *pb.TicketScore {
+	categoryScores := prepareCategoryScores(categories, container)
+	spelling, grammar, gdpr, randomness := legacyCategoryFields(categoryScores, categoryScore)
+
+	return &pb.TicketScore{
+		TicketId:   ticketID,
+		Score:      all.score(),
+		Spelling:   spelling,
+		Grammar:    grammar,
+		Gdpr:       gdpr,
+		Randomness: randomness,
+		Categories: categoryScores,
+	}
+}
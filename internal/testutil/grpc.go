@@ -0,0 +1,47 @@
+// Package testutil serves gRPC servers over in-memory connections for the
+// tests of the other packages.
+package testutil
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// BUFFER_SIZE is the buffer of the in-memory connections.
+const BUFFER_SIZE = 1 << 20
+
+// Listen starts a server with opts on an in-memory listener and stops it when
+// the test ends. register adds the services to the server.
+func Listen(t *testing.T, opts []grpc.ServerOption, register func(*grpc.Server)) *bufconn.Listener {
+	t.Helper()
+
+	lis := bufconn.Listen(BUFFER_SIZE)
+	server := grpc.NewServer(opts...)
+	register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis
+}
+
+// Serve starts a server like Listen and returns a plaintext connection to it,
+// closed when the test ends.
+func Serve(t *testing.T, opts []grpc.ServerOption, register func(*grpc.Server)) *grpc.ClientConn {
+	t.Helper()
+
+	lis := Listen(t, opts, register)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"helpdesk-ratings/internal/config"
+)
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	for _, exporter := range []string{"", EXPORTER_NONE, EXPORTER_STDOUT, EXPORTER_OTLP} {
+		shutdown, err := Setup(context.Background(), config.TracingConfig{Exporter: exporter})
+		if err != nil {
+			t.Fatalf("Expected no error for %q, got %v", exporter, err)
+		}
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("Expected no error shutting down %q, got %v", exporter, err)
+		}
+	}
+
+	if _, err := Setup(context.Background(), config.TracingConfig{Exporter: "jaeger"}); err == nil {
+		t.Fatalf("Expected an error for an unsupported exporter")
+	}
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	End(ok, nil)
+	_, failed := tracer.Start(context.Background(), "failed")
+	End(failed, errors.New("no such table"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 ended spans, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Unset || len(spans[0].Events()) != 0 {
+		t.Fatalf("Expected no error on %s, got %v", spans[0].Name(), spans[0].Status())
+	}
+	if spans[1].Status().Code != codes.Error || spans[1].Status().Description != "no such table" || len(spans[1].Events()) != 1 {
+		t.Fatalf("Expected the error on %s, got %v", spans[1].Name(), spans[1].Status())
+	}
+}
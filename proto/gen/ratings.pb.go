@@ -25,10 +25,13 @@ const (
 type ScoreEnum int32
 
 const (
-	ScoreEnum_EMPTY   ScoreEnum = 0
-	ScoreEnum_DAILY   ScoreEnum = 1
-	ScoreEnum_WEEKLY  ScoreEnum = 2
-	ScoreEnum_RATINGS ScoreEnum = 3
+	ScoreEnum_EMPTY     ScoreEnum = 0
+	ScoreEnum_DAILY     ScoreEnum = 1
+	ScoreEnum_WEEKLY    ScoreEnum = 2
+	ScoreEnum_RATINGS   ScoreEnum = 3
+	ScoreEnum_HOURLY    ScoreEnum = 4
+	ScoreEnum_MONTHLY   ScoreEnum = 5
+	ScoreEnum_QUARTERLY ScoreEnum = 6
 )
 
 // Enum value maps for ScoreEnum.
@@ -38,12 +41,18 @@ var (
 		1: "DAILY",
 		2: "WEEKLY",
 		3: "RATINGS",
+		4: "HOURLY",
+		5: "MONTHLY",
+		6: "QUARTERLY",
 	}
 	ScoreEnum_value = map[string]int32{
-		"EMPTY":   0,
-		"DAILY":   1,
-		"WEEKLY":  2,
-		"RATINGS": 3,
+		"EMPTY":     0,
+		"DAILY":     1,
+		"WEEKLY":    2,
+		"RATINGS":   3,
+		"HOURLY":    4,
+		"MONTHLY":   5,
+		"QUARTERLY": 6,
 	}
 )
 
@@ -74,29 +83,1736 @@ func (ScoreEnum) EnumDescriptor() ([]byte, []int) {
 	return file_proto_ratings_proto_rawDescGZIP(), []int{0}
 }
 
+// GRANULARITY_AUTO returns daily scores for ranges up to a month and weekly
+// scores for longer ranges.
+type Granularity int32
+
+const (
+	Granularity_GRANULARITY_AUTO      Granularity = 0
+	Granularity_GRANULARITY_HOURLY    Granularity = 1
+	Granularity_GRANULARITY_DAILY     Granularity = 2
+	Granularity_GRANULARITY_WEEKLY    Granularity = 3
+	Granularity_GRANULARITY_MONTHLY   Granularity = 4
+	Granularity_GRANULARITY_QUARTERLY Granularity = 5
+)
+
+// Enum value maps for Granularity.
+var (
+	Granularity_name = map[int32]string{
+		0: "GRANULARITY_AUTO",
+		1: "GRANULARITY_HOURLY",
+		2: "GRANULARITY_DAILY",
+		3: "GRANULARITY_WEEKLY",
+		4: "GRANULARITY_MONTHLY",
+		5: "GRANULARITY_QUARTERLY",
+	}
+	Granularity_value = map[string]int32{
+		"GRANULARITY_AUTO":      0,
+		"GRANULARITY_HOURLY":    1,
+		"GRANULARITY_DAILY":     2,
+		"GRANULARITY_WEEKLY":    3,
+		"GRANULARITY_MONTHLY":   4,
+		"GRANULARITY_QUARTERLY": 5,
+	}
+)
+
+func (x Granularity) Enum() *Granularity {
+	p := new(Granularity)
+	*p = x
+	return p
+}
+
+func (x Granularity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Granularity) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ratings_proto_enumTypes[1].Descriptor()
+}
+
+func (Granularity) Type() protoreflect.EnumType {
+	return &file_proto_ratings_proto_enumTypes[1]
+}
+
+func (x Granularity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Granularity.Descriptor instead.
+func (Granularity) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{1}
+}
+
+// time_zone is an IANA time zone name, e.g. "Europe/Tallinn". Buckets and
+// their labels follow local days in that zone. Defaults to UTC.
+// Ratings are scored with the category weight in effect when they were
+// created, use_current_weights scores all of them with today's weights.
 type AggregatedScoresRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	StartDate         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Granularity       Granularity            `protobuf:"varint,3,opt,name=granularity,proto3,enum=ratings.Granularity" json:"granularity,omitempty"`
+	TimeZone          string                 `protobuf:"bytes,4,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	UseCurrentWeights bool                   `protobuf:"varint,5,opt,name=use_current_weights,json=useCurrentWeights,proto3" json:"use_current_weights,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AggregatedScoresRequest) Reset() {
+	*x = AggregatedScoresRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregatedScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregatedScoresRequest) ProtoMessage() {}
+
+func (x *AggregatedScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregatedScoresRequest.ProtoReflect.Descriptor instead.
+func (*AggregatedScoresRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AggregatedScoresRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *AggregatedScoresRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *AggregatedScoresRequest) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_AUTO
+}
+
+func (x *AggregatedScoresRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *AggregatedScoresRequest) GetUseCurrentWeights() bool {
+	if x != nil {
+		return x.UseCurrentWeights
+	}
+	return false
+}
+
+// Without time_zone the range is absolute. With an IANA time zone name it
+// covers the whole local days in that zone from start_date to end_date.
+type OverallScoreRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	StartDate         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	TimeZone          string                 `protobuf:"bytes,3,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	UseCurrentWeights bool                   `protobuf:"varint,4,opt,name=use_current_weights,json=useCurrentWeights,proto3" json:"use_current_weights,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *OverallScoreRequest) Reset() {
+	*x = OverallScoreRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverallScoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverallScoreRequest) ProtoMessage() {}
+
+func (x *OverallScoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverallScoreRequest.ProtoReflect.Descriptor instead.
+func (*OverallScoreRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OverallScoreRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *OverallScoreRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *OverallScoreRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *OverallScoreRequest) GetUseCurrentWeights() bool {
+	if x != nil {
+		return x.UseCurrentWeights
+	}
+	return false
+}
+
+type OverallScoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OverallScore  float32                `protobuf:"fixed32,1,opt,name=overall_score,json=overallScore,proto3" json:"overall_score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OverallScoreResponse) Reset() {
+	*x = OverallScoreResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverallScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverallScoreResponse) ProtoMessage() {}
+
+func (x *OverallScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverallScoreResponse.ProtoReflect.Descriptor instead.
+func (*OverallScoreResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OverallScoreResponse) GetOverallScore() float32 {
+	if x != nil {
+		return x.OverallScore
+	}
+	return 0
+}
+
+// page_token is the next_page_token of the previous page. It is opaque and
+// only valid with the start_date, end_date and page_size it was issued for.
+type TicketScoresRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	StartDate         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	PageSize          int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken         string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	UseCurrentWeights bool                   `protobuf:"varint,5,opt,name=use_current_weights,json=useCurrentWeights,proto3" json:"use_current_weights,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *TicketScoresRequest) Reset() {
+	*x = TicketScoresRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TicketScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TicketScoresRequest) ProtoMessage() {}
+
+func (x *TicketScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TicketScoresRequest.ProtoReflect.Descriptor instead.
+func (*TicketScoresRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TicketScoresRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *TicketScoresRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *TicketScoresRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *TicketScoresRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *TicketScoresRequest) GetUseCurrentWeights() bool {
+	if x != nil {
+		return x.UseCurrentWeights
+	}
+	return false
+}
+
+type TicketScoresResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tickets       []*TicketScore         `protobuf:"bytes,1,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TicketScoresResponse) Reset() {
+	*x = TicketScoresResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TicketScoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TicketScoresResponse) ProtoMessage() {}
+
+func (x *TicketScoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TicketScoresResponse.ProtoReflect.Descriptor instead.
+func (*TicketScoresResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TicketScoresResponse) GetTickets() []*TicketScore {
+	if x != nil {
+		return x.Tickets
+	}
+	return nil
+}
+
+func (x *TicketScoresResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type TicketScore struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TicketId int64                  `protobuf:"varint,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	Score    int32                  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
+	Spelling int32 `protobuf:"varint,3,opt,name=spelling,proto3" json:"spelling,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
+	Grammar int32 `protobuf:"varint,4,opt,name=grammar,proto3" json:"grammar,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
+	Gdpr int32 `protobuf:"varint,5,opt,name=gdpr,proto3" json:"gdpr,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
+	Randomness    int32            `protobuf:"varint,6,opt,name=randomness,proto3" json:"randomness,omitempty"`
+	Categories    []*CategoryScore `protobuf:"bytes,7,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TicketScore) Reset() {
+	*x = TicketScore{}
+	mi := &file_proto_ratings_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TicketScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TicketScore) ProtoMessage() {}
+
+func (x *TicketScore) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TicketScore.ProtoReflect.Descriptor instead.
+func (*TicketScore) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TicketScore) GetTicketId() int64 {
+	if x != nil {
+		return x.TicketId
+	}
+	return 0
+}
+
+func (x *TicketScore) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/ratings.proto.
+func (x *TicketScore) GetSpelling() int32 {
+	if x != nil {
+		return x.Spelling
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/ratings.proto.
+func (x *TicketScore) GetGrammar() int32 {
+	if x != nil {
+		return x.Grammar
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/ratings.proto.
+func (x *TicketScore) GetGdpr() int32 {
+	if x != nil {
+		return x.Gdpr
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/ratings.proto.
+func (x *TicketScore) GetRandomness() int32 {
+	if x != nil {
+		return x.Randomness
+	}
+	return 0
+}
+
+func (x *TicketScore) GetCategories() []*CategoryScore {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type CompareScoresRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	StartDate           *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate             *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	ComparisonStartDate *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=comparison_start_date,json=comparisonStartDate,proto3" json:"comparison_start_date,omitempty"`
+	ComparisonEndDate   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=comparison_end_date,json=comparisonEndDate,proto3" json:"comparison_end_date,omitempty"`
+	UseCurrentWeights   bool                   `protobuf:"varint,5,opt,name=use_current_weights,json=useCurrentWeights,proto3" json:"use_current_weights,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CompareScoresRequest) Reset() {
+	*x = CompareScoresRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareScoresRequest) ProtoMessage() {}
+
+func (x *CompareScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareScoresRequest.ProtoReflect.Descriptor instead.
+func (*CompareScoresRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CompareScoresRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *CompareScoresRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *CompareScoresRequest) GetComparisonStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ComparisonStartDate
+	}
+	return nil
+}
+
+func (x *CompareScoresRequest) GetComparisonEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ComparisonEndDate
+	}
+	return nil
+}
+
+func (x *CompareScoresRequest) GetUseCurrentWeights() bool {
+	if x != nil {
+		return x.UseCurrentWeights
+	}
+	return false
+}
+
+type CompareScoresResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ComparisonStartDate *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=comparison_start_date,json=comparisonStartDate,proto3" json:"comparison_start_date,omitempty"`
+	ComparisonEndDate   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=comparison_end_date,json=comparisonEndDate,proto3" json:"comparison_end_date,omitempty"`
+	Overall             *ScoreChange           `protobuf:"bytes,3,opt,name=overall,proto3" json:"overall,omitempty"`
+	Categories          []*ScoreChange         `protobuf:"bytes,4,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CompareScoresResponse) Reset() {
+	*x = CompareScoresResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareScoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareScoresResponse) ProtoMessage() {}
+
+func (x *CompareScoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareScoresResponse.ProtoReflect.Descriptor instead.
+func (*CompareScoresResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CompareScoresResponse) GetComparisonStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ComparisonStartDate
+	}
+	return nil
+}
+
+func (x *CompareScoresResponse) GetComparisonEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ComparisonEndDate
+	}
+	return nil
+}
+
+func (x *CompareScoresResponse) GetOverall() *ScoreChange {
+	if x != nil {
+		return x.Overall
+	}
+	return nil
+}
+
+func (x *CompareScoresResponse) GetCategories() []*ScoreChange {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+// current and previous are unset when the period has no ratings, of the
+// category or at all for overall, and the changes are unset with them.
+// Categories are matched by category_id, which is 0 for overall.
+type ScoreChange struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Category       string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Current        *float32               `protobuf:"fixed32,2,opt,name=current,proto3,oneof" json:"current,omitempty"`
+	Previous       *float32               `protobuf:"fixed32,3,opt,name=previous,proto3,oneof" json:"previous,omitempty"`
+	AbsoluteChange *float32               `protobuf:"fixed32,4,opt,name=absolute_change,json=absoluteChange,proto3,oneof" json:"absolute_change,omitempty"`
+	// Percentage of the previous score, unset when the previous score is 0.
+	RelativeChange *float32 `protobuf:"fixed32,5,opt,name=relative_change,json=relativeChange,proto3,oneof" json:"relative_change,omitempty"`
+	CategoryId     int64    `protobuf:"varint,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScoreChange) Reset() {
+	*x = ScoreChange{}
+	mi := &file_proto_ratings_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreChange) ProtoMessage() {}
+
+func (x *ScoreChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreChange.ProtoReflect.Descriptor instead.
+func (*ScoreChange) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ScoreChange) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ScoreChange) GetCurrent() float32 {
+	if x != nil && x.Current != nil {
+		return *x.Current
+	}
+	return 0
+}
+
+func (x *ScoreChange) GetPrevious() float32 {
+	if x != nil && x.Previous != nil {
+		return *x.Previous
+	}
+	return 0
+}
+
+func (x *ScoreChange) GetAbsoluteChange() float32 {
+	if x != nil && x.AbsoluteChange != nil {
+		return *x.AbsoluteChange
+	}
+	return 0
+}
+
+func (x *ScoreChange) GetRelativeChange() float32 {
+	if x != nil && x.RelativeChange != nil {
+		return *x.RelativeChange
+	}
+	return 0
+}
+
+func (x *ScoreChange) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+type AgentScoresRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	StartDate         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	UseCurrentWeights bool                   `protobuf:"varint,3,opt,name=use_current_weights,json=useCurrentWeights,proto3" json:"use_current_weights,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AgentScoresRequest) Reset() {
+	*x = AgentScoresRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentScoresRequest) ProtoMessage() {}
+
+func (x *AgentScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentScoresRequest.ProtoReflect.Descriptor instead.
+func (*AgentScoresRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AgentScoresRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *AgentScoresRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *AgentScoresRequest) GetUseCurrentWeights() bool {
+	if x != nil {
+		return x.UseCurrentWeights
+	}
+	return false
+}
+
+// Agents are ordered best to worst, agents with the same score share a rank.
+type AgentScoresResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*AgentScore          `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentScoresResponse) Reset() {
+	*x = AgentScoresResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentScoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentScoresResponse) ProtoMessage() {}
+
+func (x *AgentScoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentScoresResponse.ProtoReflect.Descriptor instead.
+func (*AgentScoresResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AgentScoresResponse) GetAgents() []*AgentScore {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+type AgentScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Rank          int32                  `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`
+	Score         int32                  `protobuf:"varint,4,opt,name=score,proto3" json:"score,omitempty"`
+	Ratings       int32                  `protobuf:"varint,5,opt,name=ratings,proto3" json:"ratings,omitempty"`
+	Categories    []*CategoryScore       `protobuf:"bytes,6,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentScore) Reset() {
+	*x = AgentScore{}
+	mi := &file_proto_ratings_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentScore) ProtoMessage() {}
+
+func (x *AgentScore) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentScore.ProtoReflect.Descriptor instead.
+func (*AgentScore) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AgentScore) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *AgentScore) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AgentScore) GetRank() int32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *AgentScore) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *AgentScore) GetRatings() int32 {
+	if x != nil {
+		return x.Ratings
+	}
+	return 0
+}
+
+func (x *AgentScore) GetCategories() []*CategoryScore {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type AgentAggregatedScoresRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	AgentId           int64                  `protobuf:"varint,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	StartDate         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate           *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Granularity       Granularity            `protobuf:"varint,4,opt,name=granularity,proto3,enum=ratings.Granularity" json:"granularity,omitempty"`
+	TimeZone          string                 `protobuf:"bytes,5,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`
+	UseCurrentWeights bool                   `protobuf:"varint,6,opt,name=use_current_weights,json=useCurrentWeights,proto3" json:"use_current_weights,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AgentAggregatedScoresRequest) Reset() {
+	*x = AgentAggregatedScoresRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentAggregatedScoresRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentAggregatedScoresRequest) ProtoMessage() {}
+
+func (x *AgentAggregatedScoresRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentAggregatedScoresRequest.ProtoReflect.Descriptor instead.
+func (*AgentAggregatedScoresRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AgentAggregatedScoresRequest) GetAgentId() int64 {
+	if x != nil {
+		return x.AgentId
+	}
+	return 0
+}
+
+func (x *AgentAggregatedScoresRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *AgentAggregatedScoresRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *AgentAggregatedScoresRequest) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_AUTO
+}
+
+func (x *AgentAggregatedScoresRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *AgentAggregatedScoresRequest) GetUseCurrentWeights() bool {
+	if x != nil {
+		return x.UseCurrentWeights
+	}
+	return false
+}
+
+// outlier_threshold is the absolute z-score above which a reviewer is
+// flagged as an outlier. Defaults to 2.
+type ReviewerCalibrationRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	StartDate        *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	OutlierThreshold float64                `protobuf:"fixed64,3,opt,name=outlier_threshold,json=outlierThreshold,proto3" json:"outlier_threshold,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ReviewerCalibrationRequest) Reset() {
+	*x = ReviewerCalibrationRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewerCalibrationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewerCalibrationRequest) ProtoMessage() {}
+
+func (x *ReviewerCalibrationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewerCalibrationRequest.ProtoReflect.Descriptor instead.
+func (*ReviewerCalibrationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReviewerCalibrationRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *ReviewerCalibrationRequest) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *ReviewerCalibrationRequest) GetOutlierThreshold() float64 {
+	if x != nil {
+		return x.OutlierThreshold
+	}
+	return 0
+}
+
+type ReviewerCalibrationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Population    []*CategoryCalibration `protobuf:"bytes,1,rep,name=population,proto3" json:"population,omitempty"`
+	Reviewers     []*ReviewerCalibration `protobuf:"bytes,2,rep,name=reviewers,proto3" json:"reviewers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewerCalibrationResponse) Reset() {
+	*x = ReviewerCalibrationResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewerCalibrationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewerCalibrationResponse) ProtoMessage() {}
+
+func (x *ReviewerCalibrationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewerCalibrationResponse.ProtoReflect.Descriptor instead.
+func (*ReviewerCalibrationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReviewerCalibrationResponse) GetPopulation() []*CategoryCalibration {
+	if x != nil {
+		return x.Population
+	}
+	return nil
+}
+
+func (x *ReviewerCalibrationResponse) GetReviewers() []*ReviewerCalibration {
+	if x != nil {
+		return x.Reviewers
+	}
+	return nil
+}
+
+// deviation is the average difference between the reviewer's ratings and the
+// population mean of the same category, on the 0-5 rating scale. z_score
+// compares that deviation with the deviations of all reviewers in the range,
+// using their sample standard deviation (divided by N-1). It is 0 with fewer
+// than two reviewers or when all deviations are equal.
+type ReviewerCalibration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewerId    int64                  `protobuf:"varint,1,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Ratings       int32                  `protobuf:"varint,3,opt,name=ratings,proto3" json:"ratings,omitempty"`
+	MeanRating    float64                `protobuf:"fixed64,4,opt,name=mean_rating,json=meanRating,proto3" json:"mean_rating,omitempty"`
+	Deviation     float64                `protobuf:"fixed64,5,opt,name=deviation,proto3" json:"deviation,omitempty"`
+	ZScore        float64                `protobuf:"fixed64,6,opt,name=z_score,json=zScore,proto3" json:"z_score,omitempty"`
+	Outlier       bool                   `protobuf:"varint,7,opt,name=outlier,proto3" json:"outlier,omitempty"`
+	Categories    []*CategoryCalibration `protobuf:"bytes,8,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewerCalibration) Reset() {
+	*x = ReviewerCalibration{}
+	mi := &file_proto_ratings_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewerCalibration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewerCalibration) ProtoMessage() {}
+
+func (x *ReviewerCalibration) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewerCalibration.ProtoReflect.Descriptor instead.
+func (*ReviewerCalibration) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReviewerCalibration) GetReviewerId() int64 {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return 0
+}
+
+func (x *ReviewerCalibration) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReviewerCalibration) GetRatings() int32 {
+	if x != nil {
+		return x.Ratings
+	}
+	return 0
+}
+
+func (x *ReviewerCalibration) GetMeanRating() float64 {
+	if x != nil {
+		return x.MeanRating
+	}
+	return 0
+}
+
+func (x *ReviewerCalibration) GetDeviation() float64 {
+	if x != nil {
+		return x.Deviation
+	}
+	return 0
+}
+
+func (x *ReviewerCalibration) GetZScore() float64 {
+	if x != nil {
+		return x.ZScore
+	}
+	return 0
+}
+
+func (x *ReviewerCalibration) GetOutlier() bool {
+	if x != nil {
+		return x.Outlier
+	}
+	return false
+}
+
+func (x *ReviewerCalibration) GetCategories() []*CategoryCalibration {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type CategoryCalibration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    int64                  `protobuf:"varint,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Ratings       int32                  `protobuf:"varint,3,opt,name=ratings,proto3" json:"ratings,omitempty"`
+	MeanRating    float64                `protobuf:"fixed64,4,opt,name=mean_rating,json=meanRating,proto3" json:"mean_rating,omitempty"`
+	Deviation     float64                `protobuf:"fixed64,5,opt,name=deviation,proto3" json:"deviation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryCalibration) Reset() {
+	*x = CategoryCalibration{}
+	mi := &file_proto_ratings_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryCalibration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryCalibration) ProtoMessage() {}
+
+func (x *CategoryCalibration) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryCalibration.ProtoReflect.Descriptor instead.
+func (*CategoryCalibration) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CategoryCalibration) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *CategoryCalibration) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CategoryCalibration) GetRatings() int32 {
+	if x != nil {
+		return x.Ratings
+	}
+	return 0
+}
+
+func (x *CategoryCalibration) GetMeanRating() float64 {
+	if x != nil {
+		return x.MeanRating
+	}
+	return 0
+}
+
+func (x *CategoryCalibration) GetDeviation() float64 {
+	if x != nil {
+		return x.Deviation
+	}
+	return 0
+}
+
+// created_at defaults to the time the rating is received.
+type SubmitRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TicketId      int64                  `protobuf:"varint,1,opt,name=ticket_id,json=ticketId,proto3" json:"ticket_id,omitempty"`
+	CategoryId    int64                  `protobuf:"varint,2,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	ReviewerId    int64                  `protobuf:"varint,3,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	RevieweeId    int64                  `protobuf:"varint,4,opt,name=reviewee_id,json=revieweeId,proto3" json:"reviewee_id,omitempty"`
+	Value         int32                  `protobuf:"varint,5,opt,name=value,proto3" json:"value,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitRatingRequest) Reset() {
+	*x = SubmitRatingRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitRatingRequest) ProtoMessage() {}
+
+func (x *SubmitRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitRatingRequest.ProtoReflect.Descriptor instead.
+func (*SubmitRatingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SubmitRatingRequest) GetTicketId() int64 {
+	if x != nil {
+		return x.TicketId
+	}
+	return 0
+}
+
+func (x *SubmitRatingRequest) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *SubmitRatingRequest) GetReviewerId() int64 {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return 0
+}
+
+func (x *SubmitRatingRequest) GetRevieweeId() int64 {
+	if x != nil {
+		return x.RevieweeId
+	}
+	return 0
+}
+
+func (x *SubmitRatingRequest) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *SubmitRatingRequest) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type SubmitRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitRatingResponse) Reset() {
+	*x = SubmitRatingResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitRatingResponse) ProtoMessage() {}
+
+func (x *SubmitRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitRatingResponse.ProtoReflect.Descriptor instead.
+func (*SubmitRatingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SubmitRatingResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type SubmitRatingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SubmitRatingResult  `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Accepted      int32                  `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected      int32                  `protobuf:"varint,3,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitRatingsResponse) Reset() {
+	*x = SubmitRatingsResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitRatingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitRatingsResponse) ProtoMessage() {}
+
+func (x *SubmitRatingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitRatingsResponse.ProtoReflect.Descriptor instead.
+func (*SubmitRatingsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SubmitRatingsResponse) GetResults() []*SubmitRatingResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SubmitRatingsResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *SubmitRatingsResponse) GetRejected() int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+// index is the position of the rating in the stream. code is a gRPC status
+// code, OK when the rating was stored under id.
+type SubmitRatingResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Code          int32                  `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitRatingResult) Reset() {
+	*x = SubmitRatingResult{}
+	mi := &file_proto_ratings_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitRatingResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitRatingResult) ProtoMessage() {}
+
+func (x *SubmitRatingResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitRatingResult.ProtoReflect.Descriptor instead.
+func (*SubmitRatingResult) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SubmitRatingResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SubmitRatingResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SubmitRatingResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SubmitRatingResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListCategoriesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IncludeRetired bool                   `protobuf:"varint,1,opt,name=include_retired,json=includeRetired,proto3" json:"include_retired,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRequest) Reset() {
+	*x = ListCategoriesRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRequest) ProtoMessage() {}
+
+func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListCategoriesRequest) GetIncludeRetired() bool {
+	if x != nil {
+		return x.IncludeRetired
+	}
+	return false
+}
+
+type ListCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesResponse) Reset() {
+	*x = ListCategoriesResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesResponse) ProtoMessage() {}
+
+func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListCategoriesResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type Category struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Weight        float64                `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	RetiredAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=retired_at,json=retiredAt,proto3" json:"retired_at,omitempty"`
+	Weights       []*CategoryWeight      `protobuf:"bytes,5,rep,name=weights,proto3" json:"weights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_proto_ratings_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Category) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Category) GetRetiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetiredAt
+	}
+	return nil
+}
+
+func (x *Category) GetWeights() []*CategoryWeight {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+type CategoryWeight struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StartDate     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	EndDate       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Weight        float64                `protobuf:"fixed64,1,opt,name=weight,proto3" json:"weight,omitempty"`
+	EffectiveFrom *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AggregatedScoresRequest) Reset() {
-	*x = AggregatedScoresRequest{}
-	mi := &file_proto_ratings_proto_msgTypes[0]
+func (x *CategoryWeight) Reset() {
+	*x = CategoryWeight{}
+	mi := &file_proto_ratings_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AggregatedScoresRequest) String() string {
+func (x *CategoryWeight) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AggregatedScoresRequest) ProtoMessage() {}
+func (*CategoryWeight) ProtoMessage() {}
 
-func (x *AggregatedScoresRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ratings_proto_msgTypes[0]
+func (x *CategoryWeight) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -107,48 +1823,48 @@ func (x *AggregatedScoresRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AggregatedScoresRequest.ProtoReflect.Descriptor instead.
-func (*AggregatedScoresRequest) Descriptor() ([]byte, []int) {
-	return file_proto_ratings_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use CategoryWeight.ProtoReflect.Descriptor instead.
+func (*CategoryWeight) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *AggregatedScoresRequest) GetStartDate() *timestamppb.Timestamp {
+func (x *CategoryWeight) GetWeight() float64 {
 	if x != nil {
-		return x.StartDate
+		return x.Weight
 	}
-	return nil
+	return 0
 }
 
-func (x *AggregatedScoresRequest) GetEndDate() *timestamppb.Timestamp {
+func (x *CategoryWeight) GetEffectiveFrom() *timestamppb.Timestamp {
 	if x != nil {
-		return x.EndDate
+		return x.EffectiveFrom
 	}
 	return nil
 }
 
-type OverallScoreRequest struct {
+type CreateCategoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StartDate     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
-	EndDate       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Weight        float64                `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OverallScoreRequest) Reset() {
-	*x = OverallScoreRequest{}
-	mi := &file_proto_ratings_proto_msgTypes[1]
+func (x *CreateCategoryRequest) Reset() {
+	*x = CreateCategoryRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OverallScoreRequest) String() string {
+func (x *CreateCategoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OverallScoreRequest) ProtoMessage() {}
+func (*CreateCategoryRequest) ProtoMessage() {}
 
-func (x *OverallScoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ratings_proto_msgTypes[1]
+func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,47 +1875,160 @@ func (x *OverallScoreRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OverallScoreRequest.ProtoReflect.Descriptor instead.
-func (*OverallScoreRequest) Descriptor() ([]byte, []int) {
-	return file_proto_ratings_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CreateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *OverallScoreRequest) GetStartDate() *timestamppb.Timestamp {
+func (x *CreateCategoryRequest) GetName() string {
 	if x != nil {
-		return x.StartDate
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *OverallScoreRequest) GetEndDate() *timestamppb.Timestamp {
+func (x *CreateCategoryRequest) GetWeight() float64 {
 	if x != nil {
-		return x.EndDate
+		return x.Weight
+	}
+	return 0
+}
+
+type RenameCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameCategoryRequest) Reset() {
+	*x = RenameCategoryRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameCategoryRequest) ProtoMessage() {}
+
+func (x *RenameCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameCategoryRequest.ProtoReflect.Descriptor instead.
+func (*RenameCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RenameCategoryRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RenameCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// effective_from defaults to now and cannot be in the future.
+type ReweightCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Weight        float64                `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	EffectiveFrom *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReweightCategoryRequest) Reset() {
+	*x = ReweightCategoryRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReweightCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReweightCategoryRequest) ProtoMessage() {}
+
+func (x *ReweightCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReweightCategoryRequest.ProtoReflect.Descriptor instead.
+func (*ReweightCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ReweightCategoryRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ReweightCategoryRequest) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ReweightCategoryRequest) GetEffectiveFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveFrom
 	}
 	return nil
 }
 
-type OverallScoreResponse struct {
+type RetireCategoryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OverallScore  float32                `protobuf:"fixed32,1,opt,name=overall_score,json=overallScore,proto3" json:"overall_score,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OverallScoreResponse) Reset() {
-	*x = OverallScoreResponse{}
-	mi := &file_proto_ratings_proto_msgTypes[2]
+func (x *RetireCategoryRequest) Reset() {
+	*x = RetireCategoryRequest{}
+	mi := &file_proto_ratings_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OverallScoreResponse) String() string {
+func (x *RetireCategoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OverallScoreResponse) ProtoMessage() {}
+func (*RetireCategoryRequest) ProtoMessage() {}
 
-func (x *OverallScoreResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ratings_proto_msgTypes[2]
+func (x *RetireCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -210,14 +2039,14 @@ func (x *OverallScoreResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OverallScoreResponse.ProtoReflect.Descriptor instead.
-func (*OverallScoreResponse) Descriptor() ([]byte, []int) {
-	return file_proto_ratings_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use RetireCategoryRequest.ProtoReflect.Descriptor instead.
+func (*RetireCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *OverallScoreResponse) GetOverallScore() float32 {
+func (x *RetireCategoryRequest) GetId() int64 {
 	if x != nil {
-		return x.OverallScore
+		return x.Id
 	}
 	return 0
 }
@@ -231,7 +2060,7 @@ type AggregatedScoresResponse struct {
 
 func (x *AggregatedScoresResponse) Reset() {
 	*x = AggregatedScoresResponse{}
-	mi := &file_proto_ratings_proto_msgTypes[3]
+	mi := &file_proto_ratings_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -243,7 +2072,7 @@ func (x *AggregatedScoresResponse) String() string {
 func (*AggregatedScoresResponse) ProtoMessage() {}
 
 func (x *AggregatedScoresResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ratings_proto_msgTypes[3]
+	mi := &file_proto_ratings_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -256,7 +2085,7 @@ func (x *AggregatedScoresResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AggregatedScoresResponse.ProtoReflect.Descriptor instead.
 func (*AggregatedScoresResponse) Descriptor() ([]byte, []int) {
-	return file_proto_ratings_proto_rawDescGZIP(), []int{3}
+	return file_proto_ratings_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *AggregatedScoresResponse) GetScores() []*Score {
@@ -266,21 +2095,88 @@ func (x *AggregatedScoresResponse) GetScores() []*Score {
 	return nil
 }
 
-type Score struct {
+// csv has the columns Category, Ratings, one per period of the range and
+// Score. Scores are percentages, N/A when the category has no ratings in the
+// period.
+type ExportAggregatedScoresResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          ScoreEnum              `protobuf:"varint,1,opt,name=type,proto3,enum=ratings.ScoreEnum" json:"type,omitempty"`
-	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	Spelling      int32                  `protobuf:"varint,3,opt,name=spelling,proto3" json:"spelling,omitempty"`
-	Grammar       int32                  `protobuf:"varint,4,opt,name=grammar,proto3" json:"grammar,omitempty"`
-	Gdpr          int32                  `protobuf:"varint,5,opt,name=gdpr,proto3" json:"gdpr,omitempty"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Csv           []byte                 `protobuf:"bytes,2,opt,name=csv,proto3" json:"csv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportAggregatedScoresResponse) Reset() {
+	*x = ExportAggregatedScoresResponse{}
+	mi := &file_proto_ratings_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportAggregatedScoresResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportAggregatedScoresResponse) ProtoMessage() {}
+
+func (x *ExportAggregatedScoresResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportAggregatedScoresResponse.ProtoReflect.Descriptor instead.
+func (*ExportAggregatedScoresResponse) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ExportAggregatedScoresResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportAggregatedScoresResponse) GetCsv() []byte {
+	if x != nil {
+		return x.Csv
+	}
+	return nil
+}
+
+// The fixed category fields are kept for old clients and are only filled for
+// categories with these names. New clients should read categories instead.
+// Period rows carry inclusive start_date and end_date bounds, partial is set
+// when the requested range covers only a part of the period.
+type Score struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  ScoreEnum              `protobuf:"varint,1,opt,name=type,proto3,enum=ratings.ScoreEnum" json:"type,omitempty"`
+	Value string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
+	Spelling int32 `protobuf:"varint,3,opt,name=spelling,proto3" json:"spelling,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
+	Grammar int32 `protobuf:"varint,4,opt,name=grammar,proto3" json:"grammar,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
+	Gdpr int32 `protobuf:"varint,5,opt,name=gdpr,proto3" json:"gdpr,omitempty"`
+	// Deprecated: Marked as deprecated in proto/ratings.proto.
 	Randomness    int32                  `protobuf:"varint,6,opt,name=randomness,proto3" json:"randomness,omitempty"`
+	Categories    []*CategoryScore       `protobuf:"bytes,7,rep,name=categories,proto3" json:"categories,omitempty"`
+	StartDate     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Partial       bool                   `protobuf:"varint,10,opt,name=partial,proto3" json:"partial,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Score) Reset() {
 	*x = Score{}
-	mi := &file_proto_ratings_proto_msgTypes[4]
+	mi := &file_proto_ratings_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -292,7 +2188,7 @@ func (x *Score) String() string {
 func (*Score) ProtoMessage() {}
 
 func (x *Score) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_ratings_proto_msgTypes[4]
+	mi := &file_proto_ratings_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -305,7 +2201,7 @@ func (x *Score) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Score.ProtoReflect.Descriptor instead.
 func (*Score) Descriptor() ([]byte, []int) {
-	return file_proto_ratings_proto_rawDescGZIP(), []int{4}
+	return file_proto_ratings_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *Score) GetType() ScoreEnum {
@@ -322,6 +2218,7 @@ func (x *Score) GetValue() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in proto/ratings.proto.
 func (x *Score) GetSpelling() int32 {
 	if x != nil {
 		return x.Spelling
@@ -329,6 +2226,7 @@ func (x *Score) GetSpelling() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in proto/ratings.proto.
 func (x *Score) GetGrammar() int32 {
 	if x != nil {
 		return x.Grammar
@@ -336,6 +2234,7 @@ func (x *Score) GetGrammar() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in proto/ratings.proto.
 func (x *Score) GetGdpr() int32 {
 	if x != nil {
 		return x.Gdpr
@@ -343,6 +2242,7 @@ func (x *Score) GetGdpr() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in proto/ratings.proto.
 func (x *Score) GetRandomness() int32 {
 	if x != nil {
 		return x.Randomness
@@ -350,41 +2250,343 @@ func (x *Score) GetRandomness() int32 {
 	return 0
 }
 
+func (x *Score) GetCategories() []*CategoryScore {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *Score) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Score) GetEndDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *Score) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+// For RATINGS rows ratings is the number of ratings in the whole range and
+// score is the category score over the whole range.
+type CategoryScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    int64                  `protobuf:"varint,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Score         int32                  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	Ratings       int32                  `protobuf:"varint,4,opt,name=ratings,proto3" json:"ratings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryScore) Reset() {
+	*x = CategoryScore{}
+	mi := &file_proto_ratings_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryScore) ProtoMessage() {}
+
+func (x *CategoryScore) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ratings_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryScore.ProtoReflect.Descriptor instead.
+func (*CategoryScore) Descriptor() ([]byte, []int) {
+	return file_proto_ratings_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CategoryScore) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *CategoryScore) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CategoryScore) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *CategoryScore) GetRatings() int32 {
+	if x != nil {
+		return x.Ratings
+	}
+	return 0
+}
+
 var File_proto_ratings_proto protoreflect.FileDescriptor
 
 const file_proto_ratings_proto_rawDesc = "" +
 	"\n" +
-	"\x13proto/ratings.proto\x12\aratings\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8b\x01\n" +
+	"\x13proto/ratings.proto\x12\aratings\x1a\x1fgoogle/protobuf/timestamp.proto\"\x90\x02\n" +
 	"\x17AggregatedScoresRequest\x129\n" +
 	"\n" +
 	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
-	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\"\x87\x01\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x126\n" +
+	"\vgranularity\x18\x03 \x01(\x0e2\x14.ratings.GranularityR\vgranularity\x12\x1b\n" +
+	"\ttime_zone\x18\x04 \x01(\tR\btimeZone\x12.\n" +
+	"\x13use_current_weights\x18\x05 \x01(\bR\x11useCurrentWeights\"\xd4\x01\n" +
 	"\x13OverallScoreRequest\x129\n" +
 	"\n" +
 	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
-	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\";\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x1b\n" +
+	"\ttime_zone\x18\x03 \x01(\tR\btimeZone\x12.\n" +
+	"\x13use_current_weights\x18\x04 \x01(\bR\x11useCurrentWeights\";\n" +
 	"\x14OverallScoreResponse\x12#\n" +
-	"\roverall_score\x18\x01 \x01(\x02R\foverallScore\"B\n" +
+	"\roverall_score\x18\x01 \x01(\x02R\foverallScore\"\xf3\x01\n" +
+	"\x13TicketScoresRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12.\n" +
+	"\x13use_current_weights\x18\x05 \x01(\bR\x11useCurrentWeights\"n\n" +
+	"\x14TicketScoresResponse\x12.\n" +
+	"\atickets\x18\x01 \x03(\v2\x14.ratings.TicketScoreR\atickets\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf2\x01\n" +
+	"\vTicketScore\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\x03R\bticketId\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x05R\x05score\x12\x1e\n" +
+	"\bspelling\x18\x03 \x01(\x05B\x02\x18\x01R\bspelling\x12\x1c\n" +
+	"\agrammar\x18\x04 \x01(\x05B\x02\x18\x01R\agrammar\x12\x16\n" +
+	"\x04gdpr\x18\x05 \x01(\x05B\x02\x18\x01R\x04gdpr\x12\"\n" +
+	"\n" +
+	"randomness\x18\x06 \x01(\x05B\x02\x18\x01R\n" +
+	"randomness\x126\n" +
+	"\n" +
+	"categories\x18\a \x03(\v2\x16.ratings.CategoryScoreR\n" +
+	"categories\"\xd4\x02\n" +
+	"\x14CompareScoresRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12N\n" +
+	"\x15comparison_start_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x13comparisonStartDate\x12J\n" +
+	"\x13comparison_end_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x11comparisonEndDate\x12.\n" +
+	"\x13use_current_weights\x18\x05 \x01(\bR\x11useCurrentWeights\"\x99\x02\n" +
+	"\x15CompareScoresResponse\x12N\n" +
+	"\x15comparison_start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x13comparisonStartDate\x12J\n" +
+	"\x13comparison_end_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x11comparisonEndDate\x12.\n" +
+	"\aoverall\x18\x03 \x01(\v2\x14.ratings.ScoreChangeR\aoverall\x124\n" +
+	"\n" +
+	"categories\x18\x04 \x03(\v2\x14.ratings.ScoreChangeR\n" +
+	"categories\"\xa7\x02\n" +
+	"\vScoreChange\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x1d\n" +
+	"\acurrent\x18\x02 \x01(\x02H\x00R\acurrent\x88\x01\x01\x12\x1f\n" +
+	"\bprevious\x18\x03 \x01(\x02H\x01R\bprevious\x88\x01\x01\x12,\n" +
+	"\x0fabsolute_change\x18\x04 \x01(\x02H\x02R\x0eabsoluteChange\x88\x01\x01\x12,\n" +
+	"\x0frelative_change\x18\x05 \x01(\x02H\x03R\x0erelativeChange\x88\x01\x01\x12\x1f\n" +
+	"\vcategory_id\x18\x06 \x01(\x03R\n" +
+	"categoryIdB\n" +
+	"\n" +
+	"\b_currentB\v\n" +
+	"\t_previousB\x12\n" +
+	"\x10_absolute_changeB\x12\n" +
+	"\x10_relative_change\"\xb6\x01\n" +
+	"\x12AgentScoresRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12.\n" +
+	"\x13use_current_weights\x18\x03 \x01(\bR\x11useCurrentWeights\"B\n" +
+	"\x13AgentScoresResponse\x12+\n" +
+	"\x06agents\x18\x01 \x03(\v2\x13.ratings.AgentScoreR\x06agents\"\xb7\x01\n" +
+	"\n" +
+	"AgentScore\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04rank\x18\x03 \x01(\x05R\x04rank\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x05R\x05score\x12\x18\n" +
+	"\aratings\x18\x05 \x01(\x05R\aratings\x126\n" +
+	"\n" +
+	"categories\x18\x06 \x03(\v2\x16.ratings.CategoryScoreR\n" +
+	"categories\"\xb0\x02\n" +
+	"\x1cAgentAggregatedScoresRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\x03R\aagentId\x129\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x126\n" +
+	"\vgranularity\x18\x04 \x01(\x0e2\x14.ratings.GranularityR\vgranularity\x12\x1b\n" +
+	"\ttime_zone\x18\x05 \x01(\tR\btimeZone\x12.\n" +
+	"\x13use_current_weights\x18\x06 \x01(\bR\x11useCurrentWeights\"\xbb\x01\n" +
+	"\x1aReviewerCalibrationRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12+\n" +
+	"\x11outlier_threshold\x18\x03 \x01(\x01R\x10outlierThreshold\"\x97\x01\n" +
+	"\x1bReviewerCalibrationResponse\x12<\n" +
+	"\n" +
+	"population\x18\x01 \x03(\v2\x1c.ratings.CategoryCalibrationR\n" +
+	"population\x12:\n" +
+	"\treviewers\x18\x02 \x03(\v2\x1c.ratings.ReviewerCalibrationR\treviewers\"\x94\x02\n" +
+	"\x13ReviewerCalibration\x12\x1f\n" +
+	"\vreviewer_id\x18\x01 \x01(\x03R\n" +
+	"reviewerId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aratings\x18\x03 \x01(\x05R\aratings\x12\x1f\n" +
+	"\vmean_rating\x18\x04 \x01(\x01R\n" +
+	"meanRating\x12\x1c\n" +
+	"\tdeviation\x18\x05 \x01(\x01R\tdeviation\x12\x17\n" +
+	"\az_score\x18\x06 \x01(\x01R\x06zScore\x12\x18\n" +
+	"\aoutlier\x18\a \x01(\bR\aoutlier\x12<\n" +
+	"\n" +
+	"categories\x18\b \x03(\v2\x1c.ratings.CategoryCalibrationR\n" +
+	"categories\"\xa3\x01\n" +
+	"\x13CategoryCalibration\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\x03R\n" +
+	"categoryId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aratings\x18\x03 \x01(\x05R\aratings\x12\x1f\n" +
+	"\vmean_rating\x18\x04 \x01(\x01R\n" +
+	"meanRating\x12\x1c\n" +
+	"\tdeviation\x18\x05 \x01(\x01R\tdeviation\"\xe6\x01\n" +
+	"\x13SubmitRatingRequest\x12\x1b\n" +
+	"\tticket_id\x18\x01 \x01(\x03R\bticketId\x12\x1f\n" +
+	"\vcategory_id\x18\x02 \x01(\x03R\n" +
+	"categoryId\x12\x1f\n" +
+	"\vreviewer_id\x18\x03 \x01(\x03R\n" +
+	"reviewerId\x12\x1f\n" +
+	"\vreviewee_id\x18\x04 \x01(\x03R\n" +
+	"revieweeId\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\x05R\x05value\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"&\n" +
+	"\x14SubmitRatingResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x86\x01\n" +
+	"\x15SubmitRatingsResponse\x125\n" +
+	"\aresults\x18\x01 \x03(\v2\x1b.ratings.SubmitRatingResultR\aresults\x12\x1a\n" +
+	"\baccepted\x18\x02 \x01(\x05R\baccepted\x12\x1a\n" +
+	"\brejected\x18\x03 \x01(\x05R\brejected\"h\n" +
+	"\x12SubmitRatingResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"@\n" +
+	"\x15ListCategoriesRequest\x12'\n" +
+	"\x0finclude_retired\x18\x01 \x01(\bR\x0eincludeRetired\"K\n" +
+	"\x16ListCategoriesResponse\x121\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x11.ratings.CategoryR\n" +
+	"categories\"\xb4\x01\n" +
+	"\bCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x01R\x06weight\x129\n" +
+	"\n" +
+	"retired_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tretiredAt\x121\n" +
+	"\aweights\x18\x05 \x03(\v2\x17.ratings.CategoryWeightR\aweights\"k\n" +
+	"\x0eCategoryWeight\x12\x16\n" +
+	"\x06weight\x18\x01 \x01(\x01R\x06weight\x12A\n" +
+	"\x0eeffective_from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\reffectiveFrom\"C\n" +
+	"\x15CreateCategoryRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x01R\x06weight\";\n" +
+	"\x15RenameCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x84\x01\n" +
+	"\x17ReweightCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x01R\x06weight\x12A\n" +
+	"\x0eeffective_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\reffectiveFrom\"'\n" +
+	"\x15RetireCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"B\n" +
 	"\x18AggregatedScoresResponse\x12&\n" +
-	"\x06scores\x18\x01 \x03(\v2\x0e.ratings.ScoreR\x06scores\"\xaf\x01\n" +
+	"\x06scores\x18\x01 \x03(\v2\x0e.ratings.ScoreR\x06scores\"N\n" +
+	"\x1eExportAggregatedScoresResponse\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x10\n" +
+	"\x03csv\x18\x02 \x01(\fR\x03csv\"\x83\x03\n" +
 	"\x05Score\x12&\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x12.ratings.ScoreEnumR\x04type\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1a\n" +
-	"\bspelling\x18\x03 \x01(\x05R\bspelling\x12\x18\n" +
-	"\agrammar\x18\x04 \x01(\x05R\agrammar\x12\x12\n" +
-	"\x04gdpr\x18\x05 \x01(\x05R\x04gdpr\x12\x1e\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x1e\n" +
+	"\bspelling\x18\x03 \x01(\x05B\x02\x18\x01R\bspelling\x12\x1c\n" +
+	"\agrammar\x18\x04 \x01(\x05B\x02\x18\x01R\agrammar\x12\x16\n" +
+	"\x04gdpr\x18\x05 \x01(\x05B\x02\x18\x01R\x04gdpr\x12\"\n" +
 	"\n" +
-	"randomness\x18\x06 \x01(\x05R\n" +
-	"randomness*:\n" +
+	"randomness\x18\x06 \x01(\x05B\x02\x18\x01R\n" +
+	"randomness\x126\n" +
+	"\n" +
+	"categories\x18\a \x03(\v2\x16.ratings.CategoryScoreR\n" +
+	"categories\x129\n" +
+	"\n" +
+	"start_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x18\n" +
+	"\apartial\x18\n" +
+	" \x01(\bR\apartial\"t\n" +
+	"\rCategoryScore\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\x03R\n" +
+	"categoryId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x05R\x05score\x12\x18\n" +
+	"\aratings\x18\x04 \x01(\x05R\aratings*b\n" +
 	"\tScoreEnum\x12\t\n" +
 	"\x05EMPTY\x10\x00\x12\t\n" +
 	"\x05DAILY\x10\x01\x12\n" +
 	"\n" +
 	"\x06WEEKLY\x10\x02\x12\v\n" +
-	"\aRATINGS\x10\x032\xb5\x01\n" +
+	"\aRATINGS\x10\x03\x12\n" +
+	"\n" +
+	"\x06HOURLY\x10\x04\x12\v\n" +
+	"\aMONTHLY\x10\x05\x12\r\n" +
+	"\tQUARTERLY\x10\x06*\x9e\x01\n" +
+	"\vGranularity\x12\x14\n" +
+	"\x10GRANULARITY_AUTO\x10\x00\x12\x16\n" +
+	"\x12GRANULARITY_HOURLY\x10\x01\x12\x15\n" +
+	"\x11GRANULARITY_DAILY\x10\x02\x12\x16\n" +
+	"\x12GRANULARITY_WEEKLY\x10\x03\x12\x17\n" +
+	"\x13GRANULARITY_MONTHLY\x10\x04\x12\x19\n" +
+	"\x15GRANULARITY_QUARTERLY\x10\x052\xa9\n" +
+	"\n" +
 	"\aService\x12Z\n" +
-	"\x13GetAggregatedScores\x12 .ratings.AggregatedScoresRequest\x1a!.ratings.AggregatedScoresResponse\x12N\n" +
-	"\x0fGetOverallScore\x12\x1c.ratings.OverallScoreRequest\x1a\x1d.ratings.OverallScoreResponseB\vZ\tproto/genb\x06proto3"
+	"\x13GetAggregatedScores\x12 .ratings.AggregatedScoresRequest\x1a!.ratings.AggregatedScoresResponse\x12L\n" +
+	"\x16StreamAggregatedScores\x12 .ratings.AggregatedScoresRequest\x1a\x0e.ratings.Score0\x01\x12c\n" +
+	"\x16ExportAggregatedScores\x12 .ratings.AggregatedScoresRequest\x1a'.ratings.ExportAggregatedScoresResponse\x12N\n" +
+	"\x0fGetOverallScore\x12\x1c.ratings.OverallScoreRequest\x1a\x1d.ratings.OverallScoreResponse\x12N\n" +
+	"\x0fGetTicketScores\x12\x1c.ratings.TicketScoresRequest\x1a\x1d.ratings.TicketScoresResponse\x12N\n" +
+	"\rCompareScores\x12\x1d.ratings.CompareScoresRequest\x1a\x1e.ratings.CompareScoresResponse\x12K\n" +
+	"\x0eGetAgentScores\x12\x1b.ratings.AgentScoresRequest\x1a\x1c.ratings.AgentScoresResponse\x12d\n" +
+	"\x18GetAgentAggregatedScores\x12%.ratings.AgentAggregatedScoresRequest\x1a!.ratings.AggregatedScoresResponse\x12c\n" +
+	"\x16GetReviewerCalibration\x12#.ratings.ReviewerCalibrationRequest\x1a$.ratings.ReviewerCalibrationResponse\x12K\n" +
+	"\fSubmitRating\x12\x1c.ratings.SubmitRatingRequest\x1a\x1d.ratings.SubmitRatingResponse\x12O\n" +
+	"\rSubmitRatings\x12\x1c.ratings.SubmitRatingRequest\x1a\x1e.ratings.SubmitRatingsResponse(\x01\x12Q\n" +
+	"\x0eListCategories\x12\x1e.ratings.ListCategoriesRequest\x1a\x1f.ratings.ListCategoriesResponse\x12C\n" +
+	"\x0eCreateCategory\x12\x1e.ratings.CreateCategoryRequest\x1a\x11.ratings.Category\x12C\n" +
+	"\x0eRenameCategory\x12\x1e.ratings.RenameCategoryRequest\x1a\x11.ratings.Category\x12G\n" +
+	"\x10ReweightCategory\x12 .ratings.ReweightCategoryRequest\x1a\x11.ratings.Category\x12C\n" +
+	"\x0eRetireCategory\x12\x1e.ratings.RetireCategoryRequest\x1a\x11.ratings.CategoryB\vZ\tproto/genb\x06proto3"
 
 var (
 	file_proto_ratings_proto_rawDescOnce sync.Once
@@ -398,33 +2600,125 @@ func file_proto_ratings_proto_rawDescGZIP() []byte {
 	return file_proto_ratings_proto_rawDescData
 }
 
-var file_proto_ratings_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_ratings_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_ratings_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_ratings_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_proto_ratings_proto_goTypes = []any{
-	(ScoreEnum)(0),                   // 0: ratings.ScoreEnum
-	(*AggregatedScoresRequest)(nil),  // 1: ratings.AggregatedScoresRequest
-	(*OverallScoreRequest)(nil),      // 2: ratings.OverallScoreRequest
-	(*OverallScoreResponse)(nil),     // 3: ratings.OverallScoreResponse
-	(*AggregatedScoresResponse)(nil), // 4: ratings.AggregatedScoresResponse
-	(*Score)(nil),                    // 5: ratings.Score
-	(*timestamppb.Timestamp)(nil),    // 6: google.protobuf.Timestamp
+	(ScoreEnum)(0),                         // 0: ratings.ScoreEnum
+	(Granularity)(0),                       // 1: ratings.Granularity
+	(*AggregatedScoresRequest)(nil),        // 2: ratings.AggregatedScoresRequest
+	(*OverallScoreRequest)(nil),            // 3: ratings.OverallScoreRequest
+	(*OverallScoreResponse)(nil),           // 4: ratings.OverallScoreResponse
+	(*TicketScoresRequest)(nil),            // 5: ratings.TicketScoresRequest
+	(*TicketScoresResponse)(nil),           // 6: ratings.TicketScoresResponse
+	(*TicketScore)(nil),                    // 7: ratings.TicketScore
+	(*CompareScoresRequest)(nil),           // 8: ratings.CompareScoresRequest
+	(*CompareScoresResponse)(nil),          // 9: ratings.CompareScoresResponse
+	(*ScoreChange)(nil),                    // 10: ratings.ScoreChange
+	(*AgentScoresRequest)(nil),             // 11: ratings.AgentScoresRequest
+	(*AgentScoresResponse)(nil),            // 12: ratings.AgentScoresResponse
+	(*AgentScore)(nil),                     // 13: ratings.AgentScore
+	(*AgentAggregatedScoresRequest)(nil),   // 14: ratings.AgentAggregatedScoresRequest
+	(*ReviewerCalibrationRequest)(nil),     // 15: ratings.ReviewerCalibrationRequest
+	(*ReviewerCalibrationResponse)(nil),    // 16: ratings.ReviewerCalibrationResponse
+	(*ReviewerCalibration)(nil),            // 17: ratings.ReviewerCalibration
+	(*CategoryCalibration)(nil),            // 18: ratings.CategoryCalibration
+	(*SubmitRatingRequest)(nil),            // 19: ratings.SubmitRatingRequest
+	(*SubmitRatingResponse)(nil),           // 20: ratings.SubmitRatingResponse
+	(*SubmitRatingsResponse)(nil),          // 21: ratings.SubmitRatingsResponse
+	(*SubmitRatingResult)(nil),             // 22: ratings.SubmitRatingResult
+	(*ListCategoriesRequest)(nil),          // 23: ratings.ListCategoriesRequest
+	(*ListCategoriesResponse)(nil),         // 24: ratings.ListCategoriesResponse
+	(*Category)(nil),                       // 25: ratings.Category
+	(*CategoryWeight)(nil),                 // 26: ratings.CategoryWeight
+	(*CreateCategoryRequest)(nil),          // 27: ratings.CreateCategoryRequest
+	(*RenameCategoryRequest)(nil),          // 28: ratings.RenameCategoryRequest
+	(*ReweightCategoryRequest)(nil),        // 29: ratings.ReweightCategoryRequest
+	(*RetireCategoryRequest)(nil),          // 30: ratings.RetireCategoryRequest
+	(*AggregatedScoresResponse)(nil),       // 31: ratings.AggregatedScoresResponse
+	(*ExportAggregatedScoresResponse)(nil), // 32: ratings.ExportAggregatedScoresResponse
+	(*Score)(nil),                          // 33: ratings.Score
+	(*CategoryScore)(nil),                  // 34: ratings.CategoryScore
+	(*timestamppb.Timestamp)(nil),          // 35: google.protobuf.Timestamp
 }
 var file_proto_ratings_proto_depIdxs = []int32{
-	6, // 0: ratings.AggregatedScoresRequest.start_date:type_name -> google.protobuf.Timestamp
-	6, // 1: ratings.AggregatedScoresRequest.end_date:type_name -> google.protobuf.Timestamp
-	6, // 2: ratings.OverallScoreRequest.start_date:type_name -> google.protobuf.Timestamp
-	6, // 3: ratings.OverallScoreRequest.end_date:type_name -> google.protobuf.Timestamp
-	5, // 4: ratings.AggregatedScoresResponse.scores:type_name -> ratings.Score
-	0, // 5: ratings.Score.type:type_name -> ratings.ScoreEnum
-	1, // 6: ratings.Service.GetAggregatedScores:input_type -> ratings.AggregatedScoresRequest
-	2, // 7: ratings.Service.GetOverallScore:input_type -> ratings.OverallScoreRequest
-	4, // 8: ratings.Service.GetAggregatedScores:output_type -> ratings.AggregatedScoresResponse
-	3, // 9: ratings.Service.GetOverallScore:output_type -> ratings.OverallScoreResponse
-	8, // [8:10] is the sub-list for method output_type
-	6, // [6:8] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	35, // 0: ratings.AggregatedScoresRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 1: ratings.AggregatedScoresRequest.end_date:type_name -> google.protobuf.Timestamp
+	1,  // 2: ratings.AggregatedScoresRequest.granularity:type_name -> ratings.Granularity
+	35, // 3: ratings.OverallScoreRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 4: ratings.OverallScoreRequest.end_date:type_name -> google.protobuf.Timestamp
+	35, // 5: ratings.TicketScoresRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 6: ratings.TicketScoresRequest.end_date:type_name -> google.protobuf.Timestamp
+	7,  // 7: ratings.TicketScoresResponse.tickets:type_name -> ratings.TicketScore
+	34, // 8: ratings.TicketScore.categories:type_name -> ratings.CategoryScore
+	35, // 9: ratings.CompareScoresRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 10: ratings.CompareScoresRequest.end_date:type_name -> google.protobuf.Timestamp
+	35, // 11: ratings.CompareScoresRequest.comparison_start_date:type_name -> google.protobuf.Timestamp
+	35, // 12: ratings.CompareScoresRequest.comparison_end_date:type_name -> google.protobuf.Timestamp
+	35, // 13: ratings.CompareScoresResponse.comparison_start_date:type_name -> google.protobuf.Timestamp
+	35, // 14: ratings.CompareScoresResponse.comparison_end_date:type_name -> google.protobuf.Timestamp
+	10, // 15: ratings.CompareScoresResponse.overall:type_name -> ratings.ScoreChange
+	10, // 16: ratings.CompareScoresResponse.categories:type_name -> ratings.ScoreChange
+	35, // 17: ratings.AgentScoresRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 18: ratings.AgentScoresRequest.end_date:type_name -> google.protobuf.Timestamp
+	13, // 19: ratings.AgentScoresResponse.agents:type_name -> ratings.AgentScore
+	34, // 20: ratings.AgentScore.categories:type_name -> ratings.CategoryScore
+	35, // 21: ratings.AgentAggregatedScoresRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 22: ratings.AgentAggregatedScoresRequest.end_date:type_name -> google.protobuf.Timestamp
+	1,  // 23: ratings.AgentAggregatedScoresRequest.granularity:type_name -> ratings.Granularity
+	35, // 24: ratings.ReviewerCalibrationRequest.start_date:type_name -> google.protobuf.Timestamp
+	35, // 25: ratings.ReviewerCalibrationRequest.end_date:type_name -> google.protobuf.Timestamp
+	18, // 26: ratings.ReviewerCalibrationResponse.population:type_name -> ratings.CategoryCalibration
+	17, // 27: ratings.ReviewerCalibrationResponse.reviewers:type_name -> ratings.ReviewerCalibration
+	18, // 28: ratings.ReviewerCalibration.categories:type_name -> ratings.CategoryCalibration
+	35, // 29: ratings.SubmitRatingRequest.created_at:type_name -> google.protobuf.Timestamp
+	22, // 30: ratings.SubmitRatingsResponse.results:type_name -> ratings.SubmitRatingResult
+	25, // 31: ratings.ListCategoriesResponse.categories:type_name -> ratings.Category
+	35, // 32: ratings.Category.retired_at:type_name -> google.protobuf.Timestamp
+	26, // 33: ratings.Category.weights:type_name -> ratings.CategoryWeight
+	35, // 34: ratings.CategoryWeight.effective_from:type_name -> google.protobuf.Timestamp
+	35, // 35: ratings.ReweightCategoryRequest.effective_from:type_name -> google.protobuf.Timestamp
+	33, // 36: ratings.AggregatedScoresResponse.scores:type_name -> ratings.Score
+	0,  // 37: ratings.Score.type:type_name -> ratings.ScoreEnum
+	34, // 38: ratings.Score.categories:type_name -> ratings.CategoryScore
+	35, // 39: ratings.Score.start_date:type_name -> google.protobuf.Timestamp
+	35, // 40: ratings.Score.end_date:type_name -> google.protobuf.Timestamp
+	2,  // 41: ratings.Service.GetAggregatedScores:input_type -> ratings.AggregatedScoresRequest
+	2,  // 42: ratings.Service.StreamAggregatedScores:input_type -> ratings.AggregatedScoresRequest
+	2,  // 43: ratings.Service.ExportAggregatedScores:input_type -> ratings.AggregatedScoresRequest
+	3,  // 44: ratings.Service.GetOverallScore:input_type -> ratings.OverallScoreRequest
+	5,  // 45: ratings.Service.GetTicketScores:input_type -> ratings.TicketScoresRequest
+	8,  // 46: ratings.Service.CompareScores:input_type -> ratings.CompareScoresRequest
+	11, // 47: ratings.Service.GetAgentScores:input_type -> ratings.AgentScoresRequest
+	14, // 48: ratings.Service.GetAgentAggregatedScores:input_type -> ratings.AgentAggregatedScoresRequest
+	15, // 49: ratings.Service.GetReviewerCalibration:input_type -> ratings.ReviewerCalibrationRequest
+	19, // 50: ratings.Service.SubmitRating:input_type -> ratings.SubmitRatingRequest
+	19, // 51: ratings.Service.SubmitRatings:input_type -> ratings.SubmitRatingRequest
+	23, // 52: ratings.Service.ListCategories:input_type -> ratings.ListCategoriesRequest
+	27, // 53: ratings.Service.CreateCategory:input_type -> ratings.CreateCategoryRequest
+	28, // 54: ratings.Service.RenameCategory:input_type -> ratings.RenameCategoryRequest
+	29, // 55: ratings.Service.ReweightCategory:input_type -> ratings.ReweightCategoryRequest
+	30, // 56: ratings.Service.RetireCategory:input_type -> ratings.RetireCategoryRequest
+	31, // 57: ratings.Service.GetAggregatedScores:output_type -> ratings.AggregatedScoresResponse
+	33, // 58: ratings.Service.StreamAggregatedScores:output_type -> ratings.Score
+	32, // 59: ratings.Service.ExportAggregatedScores:output_type -> ratings.ExportAggregatedScoresResponse
+	4,  // 60: ratings.Service.GetOverallScore:output_type -> ratings.OverallScoreResponse
+	6,  // 61: ratings.Service.GetTicketScores:output_type -> ratings.TicketScoresResponse
+	9,  // 62: ratings.Service.CompareScores:output_type -> ratings.CompareScoresResponse
+	12, // 63: ratings.Service.GetAgentScores:output_type -> ratings.AgentScoresResponse
+	31, // 64: ratings.Service.GetAgentAggregatedScores:output_type -> ratings.AggregatedScoresResponse
+	16, // 65: ratings.Service.GetReviewerCalibration:output_type -> ratings.ReviewerCalibrationResponse
+	20, // 66: ratings.Service.SubmitRating:output_type -> ratings.SubmitRatingResponse
+	21, // 67: ratings.Service.SubmitRatings:output_type -> ratings.SubmitRatingsResponse
+	24, // 68: ratings.Service.ListCategories:output_type -> ratings.ListCategoriesResponse
+	25, // 69: ratings.Service.CreateCategory:output_type -> ratings.Category
+	25, // 70: ratings.Service.RenameCategory:output_type -> ratings.Category
+	25, // 71: ratings.Service.ReweightCategory:output_type -> ratings.Category
+	25, // 72: ratings.Service.RetireCategory:output_type -> ratings.Category
+	57, // [57:73] is the sub-list for method output_type
+	41, // [41:57] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_proto_ratings_proto_init() }
@@ -432,13 +2726,14 @@ func file_proto_ratings_proto_init() {
 	if File_proto_ratings_proto != nil {
 		return
 	}
+	file_proto_ratings_proto_msgTypes[8].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_ratings_proto_rawDesc), len(file_proto_ratings_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   5,
+			NumEnums:      2,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
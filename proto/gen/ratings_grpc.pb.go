@@ -19,8 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Service_GetAggregatedScores_FullMethodName = "/ratings.Service/GetAggregatedScores"
-	Service_GetOverallScore_FullMethodName     = "/ratings.Service/GetOverallScore"
+	Service_GetAggregatedScores_FullMethodName      = "/ratings.Service/GetAggregatedScores"
+	Service_StreamAggregatedScores_FullMethodName   = "/ratings.Service/StreamAggregatedScores"
+	Service_ExportAggregatedScores_FullMethodName   = "/ratings.Service/ExportAggregatedScores"
+	Service_GetOverallScore_FullMethodName          = "/ratings.Service/GetOverallScore"
+	Service_GetTicketScores_FullMethodName          = "/ratings.Service/GetTicketScores"
+	Service_CompareScores_FullMethodName            = "/ratings.Service/CompareScores"
+	Service_GetAgentScores_FullMethodName           = "/ratings.Service/GetAgentScores"
+	Service_GetAgentAggregatedScores_FullMethodName = "/ratings.Service/GetAgentAggregatedScores"
+	Service_GetReviewerCalibration_FullMethodName   = "/ratings.Service/GetReviewerCalibration"
+	Service_SubmitRating_FullMethodName             = "/ratings.Service/SubmitRating"
+	Service_SubmitRatings_FullMethodName            = "/ratings.Service/SubmitRatings"
+	Service_ListCategories_FullMethodName           = "/ratings.Service/ListCategories"
+	Service_CreateCategory_FullMethodName           = "/ratings.Service/CreateCategory"
+	Service_RenameCategory_FullMethodName           = "/ratings.Service/RenameCategory"
+	Service_ReweightCategory_FullMethodName         = "/ratings.Service/ReweightCategory"
+	Service_RetireCategory_FullMethodName           = "/ratings.Service/RetireCategory"
 )
 
 // ServiceClient is the client API for Service service.
@@ -28,7 +42,25 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ServiceClient interface {
 	GetAggregatedScores(ctx context.Context, in *AggregatedScoresRequest, opts ...grpc.CallOption) (*AggregatedScoresResponse, error)
+	// StreamAggregatedScores sends every period as soon as it is complete and
+	// the RATINGS totals last, for ranges too long to build in one response.
+	StreamAggregatedScores(ctx context.Context, in *AggregatedScoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Score], error)
+	// ExportAggregatedScores returns the GetAggregatedScores report as a CSV
+	// table with a row per category and a column per period.
+	ExportAggregatedScores(ctx context.Context, in *AggregatedScoresRequest, opts ...grpc.CallOption) (*ExportAggregatedScoresResponse, error)
 	GetOverallScore(ctx context.Context, in *OverallScoreRequest, opts ...grpc.CallOption) (*OverallScoreResponse, error)
+	GetTicketScores(ctx context.Context, in *TicketScoresRequest, opts ...grpc.CallOption) (*TicketScoresResponse, error)
+	CompareScores(ctx context.Context, in *CompareScoresRequest, opts ...grpc.CallOption) (*CompareScoresResponse, error)
+	GetAgentScores(ctx context.Context, in *AgentScoresRequest, opts ...grpc.CallOption) (*AgentScoresResponse, error)
+	GetAgentAggregatedScores(ctx context.Context, in *AgentAggregatedScoresRequest, opts ...grpc.CallOption) (*AggregatedScoresResponse, error)
+	GetReviewerCalibration(ctx context.Context, in *ReviewerCalibrationRequest, opts ...grpc.CallOption) (*ReviewerCalibrationResponse, error)
+	SubmitRating(ctx context.Context, in *SubmitRatingRequest, opts ...grpc.CallOption) (*SubmitRatingResponse, error)
+	SubmitRatings(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[SubmitRatingRequest, SubmitRatingsResponse], error)
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error)
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	ReweightCategory(ctx context.Context, in *ReweightCategoryRequest, opts ...grpc.CallOption) (*Category, error)
+	RetireCategory(ctx context.Context, in *RetireCategoryRequest, opts ...grpc.CallOption) (*Category, error)
 }
 
 type serviceClient struct {
@@ -49,6 +81,35 @@ func (c *serviceClient) GetAggregatedScores(ctx context.Context, in *AggregatedS
 	return out, nil
 }
 
+func (c *serviceClient) StreamAggregatedScores(ctx context.Context, in *AggregatedScoresRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Score], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Service_ServiceDesc.Streams[0], Service_StreamAggregatedScores_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AggregatedScoresRequest, Score]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Service_StreamAggregatedScoresClient = grpc.ServerStreamingClient[Score]
+
+func (c *serviceClient) ExportAggregatedScores(ctx context.Context, in *AggregatedScoresRequest, opts ...grpc.CallOption) (*ExportAggregatedScoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportAggregatedScoresResponse)
+	err := c.cc.Invoke(ctx, Service_ExportAggregatedScores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *serviceClient) GetOverallScore(ctx context.Context, in *OverallScoreRequest, opts ...grpc.CallOption) (*OverallScoreResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(OverallScoreResponse)
@@ -59,12 +120,153 @@ func (c *serviceClient) GetOverallScore(ctx context.Context, in *OverallScoreReq
 	return out, nil
 }
 
+func (c *serviceClient) GetTicketScores(ctx context.Context, in *TicketScoresRequest, opts ...grpc.CallOption) (*TicketScoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TicketScoresResponse)
+	err := c.cc.Invoke(ctx, Service_GetTicketScores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) CompareScores(ctx context.Context, in *CompareScoresRequest, opts ...grpc.CallOption) (*CompareScoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareScoresResponse)
+	err := c.cc.Invoke(ctx, Service_CompareScores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) GetAgentScores(ctx context.Context, in *AgentScoresRequest, opts ...grpc.CallOption) (*AgentScoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AgentScoresResponse)
+	err := c.cc.Invoke(ctx, Service_GetAgentScores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) GetAgentAggregatedScores(ctx context.Context, in *AgentAggregatedScoresRequest, opts ...grpc.CallOption) (*AggregatedScoresResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AggregatedScoresResponse)
+	err := c.cc.Invoke(ctx, Service_GetAgentAggregatedScores_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) GetReviewerCalibration(ctx context.Context, in *ReviewerCalibrationRequest, opts ...grpc.CallOption) (*ReviewerCalibrationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewerCalibrationResponse)
+	err := c.cc.Invoke(ctx, Service_GetReviewerCalibration_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) SubmitRating(ctx context.Context, in *SubmitRatingRequest, opts ...grpc.CallOption) (*SubmitRatingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitRatingResponse)
+	err := c.cc.Invoke(ctx, Service_SubmitRating_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) SubmitRatings(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[SubmitRatingRequest, SubmitRatingsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Service_ServiceDesc.Streams[1], Service_SubmitRatings_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubmitRatingRequest, SubmitRatingsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Service_SubmitRatingsClient = grpc.ClientStreamingClient[SubmitRatingRequest, SubmitRatingsResponse]
+
+func (c *serviceClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCategoriesResponse)
+	err := c.cc.Invoke(ctx, Service_ListCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, Service_CreateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, Service_RenameCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) ReweightCategory(ctx context.Context, in *ReweightCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, Service_ReweightCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *serviceClient) RetireCategory(ctx context.Context, in *RetireCategoryRequest, opts ...grpc.CallOption) (*Category, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Category)
+	err := c.cc.Invoke(ctx, Service_RetireCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ServiceServer is the server API for Service service.
 // All implementations must embed UnimplementedServiceServer
 // for forward compatibility.
 type ServiceServer interface {
 	GetAggregatedScores(context.Context, *AggregatedScoresRequest) (*AggregatedScoresResponse, error)
+	// StreamAggregatedScores sends every period as soon as it is complete and
+	// the RATINGS totals last, for ranges too long to build in one response.
+	StreamAggregatedScores(*AggregatedScoresRequest, grpc.ServerStreamingServer[Score]) error
+	// ExportAggregatedScores returns the GetAggregatedScores report as a CSV
+	// table with a row per category and a column per period.
+	ExportAggregatedScores(context.Context, *AggregatedScoresRequest) (*ExportAggregatedScoresResponse, error)
 	GetOverallScore(context.Context, *OverallScoreRequest) (*OverallScoreResponse, error)
+	GetTicketScores(context.Context, *TicketScoresRequest) (*TicketScoresResponse, error)
+	CompareScores(context.Context, *CompareScoresRequest) (*CompareScoresResponse, error)
+	GetAgentScores(context.Context, *AgentScoresRequest) (*AgentScoresResponse, error)
+	GetAgentAggregatedScores(context.Context, *AgentAggregatedScoresRequest) (*AggregatedScoresResponse, error)
+	GetReviewerCalibration(context.Context, *ReviewerCalibrationRequest) (*ReviewerCalibrationResponse, error)
+	SubmitRating(context.Context, *SubmitRatingRequest) (*SubmitRatingResponse, error)
+	SubmitRatings(grpc.ClientStreamingServer[SubmitRatingRequest, SubmitRatingsResponse]) error
+	ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error)
+	CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error)
+	RenameCategory(context.Context, *RenameCategoryRequest) (*Category, error)
+	ReweightCategory(context.Context, *ReweightCategoryRequest) (*Category, error)
+	RetireCategory(context.Context, *RetireCategoryRequest) (*Category, error)
 	mustEmbedUnimplementedServiceServer()
 }
 
@@ -78,9 +280,51 @@ type UnimplementedServiceServer struct{}
 func (UnimplementedServiceServer) GetAggregatedScores(context.Context, *AggregatedScoresRequest) (*AggregatedScoresResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAggregatedScores not implemented")
 }
+func (UnimplementedServiceServer) StreamAggregatedScores(*AggregatedScoresRequest, grpc.ServerStreamingServer[Score]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAggregatedScores not implemented")
+}
+func (UnimplementedServiceServer) ExportAggregatedScores(context.Context, *AggregatedScoresRequest) (*ExportAggregatedScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportAggregatedScores not implemented")
+}
 func (UnimplementedServiceServer) GetOverallScore(context.Context, *OverallScoreRequest) (*OverallScoreResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOverallScore not implemented")
 }
+func (UnimplementedServiceServer) GetTicketScores(context.Context, *TicketScoresRequest) (*TicketScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicketScores not implemented")
+}
+func (UnimplementedServiceServer) CompareScores(context.Context, *CompareScoresRequest) (*CompareScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareScores not implemented")
+}
+func (UnimplementedServiceServer) GetAgentScores(context.Context, *AgentScoresRequest) (*AgentScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgentScores not implemented")
+}
+func (UnimplementedServiceServer) GetAgentAggregatedScores(context.Context, *AgentAggregatedScoresRequest) (*AggregatedScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgentAggregatedScores not implemented")
+}
+func (UnimplementedServiceServer) GetReviewerCalibration(context.Context, *ReviewerCalibrationRequest) (*ReviewerCalibrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviewerCalibration not implemented")
+}
+func (UnimplementedServiceServer) SubmitRating(context.Context, *SubmitRatingRequest) (*SubmitRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitRating not implemented")
+}
+func (UnimplementedServiceServer) SubmitRatings(grpc.ClientStreamingServer[SubmitRatingRequest, SubmitRatingsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SubmitRatings not implemented")
+}
+func (UnimplementedServiceServer) ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
+}
+func (UnimplementedServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedServiceServer) RenameCategory(context.Context, *RenameCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameCategory not implemented")
+}
+func (UnimplementedServiceServer) ReweightCategory(context.Context, *ReweightCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReweightCategory not implemented")
+}
+func (UnimplementedServiceServer) RetireCategory(context.Context, *RetireCategoryRequest) (*Category, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetireCategory not implemented")
+}
 func (UnimplementedServiceServer) mustEmbedUnimplementedServiceServer() {}
 func (UnimplementedServiceServer) testEmbeddedByValue()                 {}
 
@@ -120,6 +364,35 @@ func _Service_GetAggregatedScores_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Service_StreamAggregatedScores_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AggregatedScoresRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ServiceServer).StreamAggregatedScores(m, &grpc.GenericServerStream[AggregatedScoresRequest, Score]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Service_StreamAggregatedScoresServer = grpc.ServerStreamingServer[Score]
+
+func _Service_ExportAggregatedScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AggregatedScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).ExportAggregatedScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_ExportAggregatedScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).ExportAggregatedScores(ctx, req.(*AggregatedScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Service_GetOverallScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(OverallScoreRequest)
 	if err := dec(in); err != nil {
@@ -138,6 +411,211 @@ func _Service_GetOverallScore_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Service_GetTicketScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TicketScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).GetTicketScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_GetTicketScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).GetTicketScores(ctx, req.(*TicketScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_CompareScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).CompareScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_CompareScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).CompareScores(ctx, req.(*CompareScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_GetAgentScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).GetAgentScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_GetAgentScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).GetAgentScores(ctx, req.(*AgentScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_GetAgentAggregatedScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AgentAggregatedScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).GetAgentAggregatedScores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_GetAgentAggregatedScores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).GetAgentAggregatedScores(ctx, req.(*AgentAggregatedScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_GetReviewerCalibration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewerCalibrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).GetReviewerCalibration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_GetReviewerCalibration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).GetReviewerCalibration(ctx, req.(*ReviewerCalibrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_SubmitRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).SubmitRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_SubmitRating_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).SubmitRating(ctx, req.(*SubmitRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_SubmitRatings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ServiceServer).SubmitRatings(&grpc.GenericServerStream[SubmitRatingRequest, SubmitRatingsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Service_SubmitRatingsServer = grpc.ClientStreamingServer[SubmitRatingRequest, SubmitRatingsResponse]
+
+func _Service_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_ListCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).ListCategories(ctx, req.(*ListCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_RenameCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).RenameCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_RenameCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).RenameCategory(ctx, req.(*RenameCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_ReweightCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReweightCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).ReweightCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_ReweightCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).ReweightCategory(ctx, req.(*ReweightCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Service_RetireCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetireCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ServiceServer).RetireCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Service_RetireCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ServiceServer).RetireCategory(ctx, req.(*RetireCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Service_ServiceDesc is the grpc.ServiceDesc for Service service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -149,11 +627,70 @@ var Service_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAggregatedScores",
 			Handler:    _Service_GetAggregatedScores_Handler,
 		},
+		{
+			MethodName: "ExportAggregatedScores",
+			Handler:    _Service_ExportAggregatedScores_Handler,
+		},
 		{
 			MethodName: "GetOverallScore",
 			Handler:    _Service_GetOverallScore_Handler,
 		},
+		{
+			MethodName: "GetTicketScores",
+			Handler:    _Service_GetTicketScores_Handler,
+		},
+		{
+			MethodName: "CompareScores",
+			Handler:    _Service_CompareScores_Handler,
+		},
+		{
+			MethodName: "GetAgentScores",
+			Handler:    _Service_GetAgentScores_Handler,
+		},
+		{
+			MethodName: "GetAgentAggregatedScores",
+			Handler:    _Service_GetAgentAggregatedScores_Handler,
+		},
+		{
+			MethodName: "GetReviewerCalibration",
+			Handler:    _Service_GetReviewerCalibration_Handler,
+		},
+		{
+			MethodName: "SubmitRating",
+			Handler:    _Service_SubmitRating_Handler,
+		},
+		{
+			MethodName: "ListCategories",
+			Handler:    _Service_ListCategories_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _Service_CreateCategory_Handler,
+		},
+		{
+			MethodName: "RenameCategory",
+			Handler:    _Service_RenameCategory_Handler,
+		},
+		{
+			MethodName: "ReweightCategory",
+			Handler:    _Service_ReweightCategory_Handler,
+		},
+		{
+			MethodName: "RetireCategory",
+			Handler:    _Service_RetireCategory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAggregatedScores",
+			Handler:       _Service_StreamAggregatedScores_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubmitRatings",
+			Handler:       _Service_SubmitRatings_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/ratings.proto",
 }